@@ -0,0 +1,66 @@
+package binpack
+
+// Axis identifies the coordinate shared by aligned rectangles.
+type Axis int
+
+const (
+	// AxisX aligns the left edges of two rectangles, so they share an
+	// x-coordinate.
+	AxisX Axis = iota
+	// AxisY aligns the top edges of two rectangles, so they share a
+	// y-coordinate.
+	AxisY
+)
+
+// Aligner is an optional interface for Packables whose rectangles must share
+// an edge coordinate with another rectangle, such as pairs in a comparison
+// collage. The anchor is placed first and the dependent rectangle is placed
+// immediately after it, at a position that shares the coordinate.
+//
+// Constraints that cannot all be satisfied are resolved as follows:
+//   - If the anchor is not placed, because it did not fit, lost to an
+//     alternative in its exclusive group, or was dropped by the limit, the
+//     dependent is placed without the constraint.
+//   - If constraints form a cycle, the first rectangle of the cycle reached in
+//     size order is placed without its constraint.
+//   - If the dependent cannot be aligned within the limits, it is placed
+//     without the constraint.
+//
+// Rectangles in exclusive groups wait for their anchor but are not aligned
+// with it.
+type Aligner interface {
+	// AlignWith returns the rectangle that the rectangle at index n must
+	// align with and the axis they share, or false if n is unconstrained.
+	AlignWith(n int) (other int, axis Axis, ok bool)
+}
+
+// alignment constrains a rectangle to share a coordinate with its anchor.
+type alignment struct {
+	axis   Axis
+	anchor placement
+	active bool
+}
+
+// allows returns true if a rectangle at (x, y) satisfies the alignment.
+func (a alignment) allows(x, y int) bool {
+	if !a.active {
+		return true
+	}
+	if a.axis == AxisX {
+		return x == a.anchor.x
+	}
+	return y == a.anchor.y
+}
+
+// fallback returns a position that is free of the placements within b and
+// satisfies the alignment. Without an alignment, it is the top-right corner
+// of b.
+func (a alignment) fallback(b bounds) (int, int) {
+	if !a.active {
+		return b.maxX, b.minY
+	}
+	if a.axis == AxisX {
+		return a.anchor.x, b.maxY
+	}
+	return b.maxX, a.anchor.y
+}
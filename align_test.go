@@ -0,0 +1,121 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testAlignment describes the anchor and axis of an aligned rectangle.
+type testAlignment struct {
+	other int
+	axis  binpack.Axis
+}
+
+// testAlignPackable extends testPackable with alignment constraints.
+type testAlignPackable struct {
+	*testPackable
+	alignments map[int]testAlignment
+}
+
+// Ensure that testAlignPackable implements the binpack.Aligner interface.
+var _ binpack.Aligner = (*testAlignPackable)(nil)
+
+// AlignWith returns the alignment of the rectangle at the specified index.
+func (tp *testAlignPackable) AlignWith(n int) (int, binpack.Axis, bool) {
+	a, ok := tp.alignments[n]
+	return a.other, a.axis, ok
+}
+
+// TestPackWithOptions_Align verifies that aligned rectangles share the
+// coordinate of their anchor.
+func TestPackWithOptions_Align(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		axis binpack.Axis
+	}{
+		{name: "AxisX", axis: binpack.AxisX},
+		{name: "AxisY", axis: binpack.AxisY},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: align a large rectangle with a small anchor.
+			tp := &testAlignPackable{
+				testPackable: newTestPackable([]binpack.Rectangle{
+					{Width: 100, Height: 100},
+					{Width: 30, Height: 30},
+					{Width: 80, Height: 60},
+				}),
+				alignments: map[int]testAlignment{2: {other: 1, axis: tt.axis}},
+			}
+
+			// Act: pack the rectangles.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+			// Assert: the dependent should share the anchor's coordinate.
+			require.NoError(t, err)
+			requireNoOverlap(t, layout)
+			if tt.axis == binpack.AxisX {
+				require.Equal(t, tp.placements[1].x, tp.placements[2].x, "expected matching x-coordinates")
+			} else {
+				require.Equal(t, tp.placements[1].y, tp.placements[2].y, "expected matching y-coordinates")
+			}
+		})
+	}
+}
+
+// TestPackWithOptions_AlignCycle verifies that cyclic constraints are resolved
+// and every rectangle is placed.
+func TestPackWithOptions_AlignCycle(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: align two rectangles with each other.
+	tp := &testAlignPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 100, Height: 100},
+			{Width: 50, Height: 40},
+		}),
+		alignments: map[int]testAlignment{
+			0: {other: 1, axis: binpack.AxisY},
+			1: {other: 0, axis: binpack.AxisY},
+		},
+	}
+
+	// Act: pack the rectangles.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: both rectangles should be placed and aligned.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, 2)
+	require.Equal(t, tp.placements[0].y, tp.placements[1].y, "expected matching y-coordinates")
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_AlignUnsatisfiable verifies that a constraint which
+// cannot be satisfied within the limits is dropped.
+func TestPackWithOptions_AlignUnsatisfiable(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: align a rectangle to the top of a full-width anchor.
+	tp := &testAlignPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 100, Height: 50},
+			{Width: 100, Height: 50},
+		}),
+		alignments: map[int]testAlignment{1: {other: 0, axis: binpack.AxisY}},
+	}
+
+	// Act: pack the rectangles into a space that only fits them stacked.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{MaxWidth: 100})
+
+	// Assert: both rectangles should be placed without the alignment.
+	require.NoError(t, err)
+	require.Equal(t, 100, layout.Width, "expected width 100")
+	require.Equal(t, 100, layout.Height, "expected height 100")
+	requireNoOverlap(t, layout)
+}
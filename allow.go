@@ -0,0 +1,38 @@
+package binpack
+
+import "errors"
+
+// ErrNotAllowed is returned by PackWithOptions when an Allower vetoes every
+// position considered for a rectangle in a layout without size limits.
+var ErrNotAllowed = errors.New("binpack: no allowed position")
+
+// Allower is an optional interface for Packables whose rectangles cannot be
+// placed at some positions, such as across the fold of a printed sheet.
+// Every candidate position is checked, and those that are not allowed are
+// skipped. Positions are in the coordinates used while packing: those of the
+// canvas with Options.Regions and of the pins with Options.KeepPinPositions,
+// and otherwise relative to the first rectangle placed, which is at (0, 0)
+// before the layout is shifted to start at the edge margin. A rectangle that
+// is not allowed at any candidate is left unplaced, and PackWithOptions
+// reports it with ErrNotAllowed if the layout has no size limits, where
+// nothing else can prevent a placement, or ErrTooLarge otherwise.
+type Allower interface {
+	// Allowed returns true if the rectangle at index n may be placed with
+	// its top-left corner at (x, y).
+	Allowed(n, x, y int) bool
+}
+
+// allowedAt returns a function that reports whether the rectangle at index n
+// of p may be placed at a position, or nil if p does not implement Allower.
+func allowedAt(p Packable, n int) func(x, y int) bool {
+	var a, ok = optional[Allower](p)
+	if !ok {
+		return nil
+	}
+	return func(x, y int) bool { return a.Allowed(n, x, y) }
+}
+
+// permits returns true if the constraints allow the position (x, y).
+func (c constraints) permits(x, y int) bool {
+	return c.allowed == nil || c.allowed(x, y)
+}
@@ -0,0 +1,146 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testAllowPackable extends testPackable with a fold line that no rectangle
+// may cross.
+type testAllowPackable struct {
+	*testPackable
+	fold int
+}
+
+// Ensure that testAllowPackable implements the binpack.Allower interface.
+var _ binpack.Allower = (*testAllowPackable)(nil)
+
+// Allowed returns false if the rectangle would cross the fold.
+func (tp *testAllowPackable) Allowed(n, x, y int) bool {
+	return x >= tp.fold || x+tp.rectangles[n].Width <= tp.fold
+}
+
+// TestAllower verifies that rectangles are never placed at positions the
+// Packable does not allow.
+func TestAllower(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+	}{
+		{name: "Unbounded", opts: binpack.Options{}},
+		{name: "Bounded", opts: binpack.Options{MaxWidth: 120, MaxHeight: 120}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: a fold 45 pixels from the left edge.
+			tp := &testAllowPackable{testPackable: newTestPackable([]binpack.Rectangle{
+				{Width: 40, Height: 40},
+				{Width: 30, Height: 30},
+				{Width: 30, Height: 30},
+				{Width: 20, Height: 20},
+			}), fold: 45}
+
+			// Act: pack around the fold.
+			layout, err := binpack.PackWithOptions(tp, tt.opts)
+
+			// Assert: no rectangle should straddle the fold.
+			require.NoError(t, err)
+			for _, p := range layout.Placements {
+				require.True(t, p.X >= 45 || p.X+p.Width <= 45, "rectangle %d crosses the fold at x=%d", p.Index, p.X)
+			}
+			requireNoOverlap(t, layout)
+		})
+	}
+}
+
+// testColumnPackable extends testPackable with the first column reserved
+// for the first rectangle.
+type testColumnPackable struct {
+	*testPackable
+}
+
+// Ensure that testColumnPackable implements the binpack.Allower interface.
+var _ binpack.Allower = (*testColumnPackable)(nil)
+
+// Allowed returns false if a rectangle other than the first would be placed
+// at the left edge.
+func (tp *testColumnPackable) Allowed(n, x, y int) bool {
+	return n == 0 || x != 0
+}
+
+// TestPackBest_Allower verifies that every attempt of PackBest honours the
+// positions the Packable does not allow, as PackWithOptions does.
+func TestPackBest_Allower(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: four squares that would otherwise form a 2x2 block.
+	rectangles := []binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+	}
+	tp := &testColumnPackable{testPackable: newTestPackable(rectangles)}
+
+	// Act: keep the best of several attempts.
+	layout, _ := binpack.PackBest(tp, binpack.Options{Attempts: 6, Seed: 1})
+
+	// Assert: only the first rectangle should be at the left edge.
+	require.Len(t, layout.Placements, len(rectangles))
+	for _, p := range layout.Placements {
+		require.True(t, p.Index == 0 || p.X != 0, "rectangle %d is at x=0", p.Index)
+	}
+	requireNoOverlap(t, layout)
+}
+
+// testNowherePackable extends testPackable with a rectangle that is allowed
+// nowhere.
+type testNowherePackable struct {
+	*testPackable
+}
+
+// Ensure that testNowherePackable implements the binpack.Allower interface.
+var _ binpack.Allower = (*testNowherePackable)(nil)
+
+// Allowed returns false for the second rectangle.
+func (tp *testNowherePackable) Allowed(n, x, y int) bool {
+	return n != 1
+}
+
+// TestPackWithOptions_NotAllowed verifies that a rectangle vetoed everywhere
+// in an unbounded layout is reported as not allowed rather than too large.
+func TestPackWithOptions_NotAllowed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+		err  error
+	}{
+		{name: "Unbounded", opts: binpack.Options{}, err: binpack.ErrNotAllowed},
+		{name: "Bounded", opts: binpack.Options{MaxWidth: 100}, err: binpack.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: a Packable that allows the second rectangle nowhere.
+			tp := &testNowherePackable{testPackable: newTestPackable([]binpack.Rectangle{{Width: 10, Height: 10}, {Width: 10, Height: 10}})}
+
+			// Act: pack both rectangles.
+			_, err := binpack.PackWithOptions(tp, tt.opts)
+
+			// Assert: the vetoed rectangle is reported, or the limit is if it is set.
+			require.ErrorIs(t, err, tt.err)
+			if tt.err == binpack.ErrNotAllowed {
+				require.ErrorContains(t, err, "rectangles [1]")
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package binpack
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrPlacement is returned by the checker of Audited when a rectangle was not
+// placed exactly once.
+var ErrPlacement = errors.New("binpack: rectangles were not placed exactly once")
+
+// Audited wraps p to record every call to Place, for debugging custom
+// Packables. The returned function checks that Place was called exactly once
+// for every index from 0 to p.Len()-1, such as after Pack returns, and
+// reports each index that was not. Rectangles that are deliberately left
+// alone, such as gaps and those PackBestEffort leaves unplaced, are reported
+// too. The wrapper passes on the optional interfaces of p, such as Pinner,
+// Skipper and Hasher, so it packs the same way as p.
+func Audited(p Packable) (Packable, func() error) {
+	var a = &audited{forwarded: forwarded{p}, calls: make([]int, p.Len())}
+	return a, a.check
+}
+
+// audited records the calls to Place on a Packable.
+type audited struct {
+	forwarded
+	mu    sync.Mutex
+	calls []int
+}
+
+// Place records the call and passes it on.
+func (a *audited) Place(n, x, y int) {
+	a.mu.Lock()
+	if n >= 0 && n < len(a.calls) {
+		a.calls[n]++
+	}
+	a.mu.Unlock()
+	a.Packable.Place(n, x, y)
+}
+
+// check returns an error listing the indices that were not placed exactly
+// once.
+func (a *audited) check() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var problems []string
+	for n, calls := range a.calls {
+		if calls != 1 {
+			problems = append(problems, fmt.Sprintf("%d placed %d times", n, calls))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrPlacement, strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// forwarded wraps a Packable and passes on its optional interfaces, so that a
+// wrapper packs the same way as the Packable it wraps. It has the methods of
+// every optional interface, so the packer looks them up with optional, which
+// only finds those the wrapped Packable implements.
+type forwarded struct {
+	Packable
+}
+
+// wrapper is implemented by Packables that wrap another and pass on its
+// optional interfaces.
+type wrapper interface {
+	unwrap() Packable
+}
+
+// unwrap returns the wrapped Packable.
+func (f forwarded) unwrap() Packable {
+	return f.Packable
+}
+
+// optional returns p as the optional interface T, if p implements it. A
+// wrapper only implements T if the Packable it wraps does, even though it has
+// the methods of T. Skipper is the exception, as wrappers add gaps of their
+// own, and is always looked up directly.
+func optional[T any](p Packable) (T, bool) {
+	if w, ok := p.(wrapper); ok {
+		if _, ok := optional[T](w.unwrap()); !ok {
+			var none T
+			return none, false
+		}
+	}
+	var t, ok = p.(T)
+	return t, ok
+}
+
+// implements returns true if p implements the optional interface T, as
+// looked up by optional.
+func implements[T any](p Packable) bool {
+	var _, ok = optional[T](p)
+	return ok
+}
+
+// Skip passes on the gaps of the wrapped Packable.
+func (f forwarded) Skip(n int) bool {
+	return skipper(f.Packable)(n)
+}
+
+// Pin passes on the pins of the wrapped Packable.
+func (f forwarded) Pin(n int) (int, int, bool) {
+	if pinner, ok := optional[Pinner](f.Packable); ok {
+		return pinner.Pin(n)
+	}
+	return 0, 0, false
+}
+
+// Group passes on the exclusive groups of the wrapped Packable.
+func (f forwarded) Group(n int) (int, bool) {
+	if g, ok := optional[ExclusiveGroup](f.Packable); ok {
+		return g.Group(n)
+	}
+	return 0, false
+}
+
+// AlignWith passes on the alignments of the wrapped Packable.
+func (f forwarded) AlignWith(n int) (int, Axis, bool) {
+	if aligner, ok := optional[Aligner](f.Packable); ok {
+		return aligner.AlignWith(n)
+	}
+	return 0, 0, false
+}
+
+// Centrality passes on the centrality of the wrapped Packable.
+func (f forwarded) Centrality(n int) float64 {
+	if c, ok := optional[Centralizer](f.Packable); ok {
+		return c.Centrality(n)
+	}
+	return 0
+}
+
+// Background passes on the backgrounds of the wrapped Packable.
+func (f forwarded) Background(n int) bool {
+	return backgrounder(f.Packable)(n)
+}
+
+// Allowed passes on the allowed positions of the wrapped Packable, which
+// default to every position.
+func (f forwarded) Allowed(n, x, y int) bool {
+	if a, ok := optional[Allower](f.Packable); ok {
+		return a.Allowed(n, x, y)
+	}
+	return true
+}
+
+// MirrorPair passes on the mirrored pairs of the wrapped Packable.
+func (f forwarded) MirrorPair(n int) (int, bool) {
+	if m, ok := optional[Mirrorer](f.Packable); ok {
+		return m.MirrorPair(n)
+	}
+	return 0, false
+}
+
+// CornerRadius passes on the corner radii of the wrapped Packable, which
+// default to square corners.
+func (f forwarded) CornerRadius(n int) int {
+	if r, ok := optional[Rounder](f.Packable); ok {
+		return r.CornerRadius(n)
+	}
+	return 0
+}
+
+// Weight passes on the weights of the wrapped Packable, which default to the
+// area of each rectangle as in PackKnapsack.
+func (f forwarded) Weight(n int) int {
+	if weigher, ok := optional[Weigher](f.Packable); ok {
+		return weigher.Weight(n)
+	}
+	return f.Packable.Rectangle(n).Area()
+}
+
+// SizeRange passes on the size ranges of the wrapped Packable, which default
+// to the size of each rectangle so that it never shrinks.
+func (f forwarded) SizeRange(n int) (Rectangle, Rectangle) {
+	if flexible, ok := optional[Flexible](f.Packable); ok {
+		return flexible.SizeRange(n)
+	}
+	var r = f.Packable.Rectangle(n)
+	return r, r
+}
+
+// Resize passes on the chosen sizes to the wrapped Packable.
+func (f forwarded) Resize(n, w, h int) {
+	if flexible, ok := optional[Flexible](f.Packable); ok {
+		flexible.Resize(n, w, h)
+	}
+}
+
+// Hash passes on the hashes of the wrapped Packable. It is only looked up if
+// the wrapped Packable implements Hasher, and otherwise returns the index of
+// each rectangle so that none are repeated.
+func (f forwarded) Hash(n int) uint64 {
+	if h, ok := optional[Hasher](f.Packable); ok {
+		return h.Hash(n)
+	}
+	return uint64(n)
+}
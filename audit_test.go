@@ -0,0 +1,156 @@
+package binpack_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAudited verifies that the checker passes when every rectangle is placed
+// and reports those that are left out.
+func TestAudited(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+		err  string
+	}{
+		{name: "AllPlaced", opts: binpack.Options{}},
+		{name: "Unplaced", opts: binpack.Options{MaxWidth: 30, MaxHeight: 30}, err: "1 placed 0 times"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: one rectangle fills the bin, leaving no room for the other.
+			tp := newTestPackable([]binpack.Rectangle{{Width: 30, Height: 30}, {Width: 20, Height: 20}})
+			p, check := binpack.Audited(tp)
+
+			// Act: pack the audited Packable and check it.
+			binpack.PackBestEffort(p, tt.opts)
+			err := check()
+
+			// Assert: the rectangle left out is reported as placed zero times.
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, binpack.ErrPlacement)
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+// TestAudited_OptionalInterfaces verifies that the wrapper packs the same way
+// as the Packable it wraps.
+func TestAudited_OptionalInterfaces(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pin two rectangles apart.
+	tp := &testPinPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{{Width: 10, Height: 10}, {Width: 10, Height: 10}}),
+		pins:         map[int]image.Point{0: {X: 0, Y: 0}, 1: {X: 50, Y: 50}},
+	}
+	p, check := binpack.Audited(tp)
+
+	// Act: pack the audited Packable.
+	layout, err := binpack.PackWithOptions(p, binpack.Options{})
+
+	// Assert: the pin is honoured and every rectangle was placed once.
+	require.NoError(t, err)
+	require.Equal(t, 60, layout.Width)
+	require.NoError(t, check())
+}
+
+// TestAudited_Hasher verifies that the wrapper passes on the hashes of the
+// Packable, so that repeated rectangles are packed once as without it.
+func TestAudited_Hasher(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: three identical tiles.
+	rectangles := []binpack.Rectangle{{Width: 10, Height: 10}, {Width: 10, Height: 10}, {Width: 10, Height: 10}}
+	hashes := []uint64{1, 1, 1}
+	expected, err := binpack.PackWithOptions(&testHashPackable{testPackable: newTestPackable(rectangles), hashes: hashes}, binpack.Options{})
+	require.NoError(t, err)
+	p, check := binpack.Audited(&testHashPackable{testPackable: newTestPackable(rectangles), hashes: hashes})
+
+	// Act: pack the audited Packable.
+	layout, err := binpack.PackWithOptions(p, binpack.Options{})
+
+	// Assert: the tiles share one placement, and every rectangle was placed once.
+	require.NoError(t, err)
+	require.Equal(t, expected, layout)
+	require.Equal(t, 10, layout.Width)
+	require.Equal(t, 10, layout.Height)
+	require.NoError(t, check())
+}
+
+// TestAudited_SameAsPackable verifies that the wrapper packs the same way as
+// the Packable it wraps, passing on only the optional interfaces it
+// implements.
+func TestAudited_SameAsPackable(t *testing.T) {
+	t.Parallel()
+
+	rectangles := randomRectangles(30)
+	tests := []struct {
+		name     string
+		packable func() binpack.Packable
+		opts     binpack.Options
+	}{
+		{name: "Plain", packable: func() binpack.Packable { return newTestPackable(rectangles) }},
+		{name: "Auto", packable: func() binpack.Packable {
+			return newTestPackable([]binpack.Rectangle{{Width: 27, Height: 3}, {Width: 3, Height: 30}, {Width: 26, Height: 24}})
+		}, opts: binpack.Options{Heuristic: binpack.HeuristicAuto}},
+		{name: "TooLarge", packable: func() binpack.Packable { return newTestPackable(rectangles) }, opts: binpack.Options{MaxWidth: 150, MaxHeight: 150}},
+		{name: "Pinner", packable: func() binpack.Packable {
+			return &testPinPackable{testPackable: newTestPackable(rectangles), pins: map[int]image.Point{3: {X: 40, Y: 40}}}
+		}},
+		{name: "Hasher", packable: func() binpack.Packable {
+			return &testHashPackable{testPackable: newTestPackable(rectangles[:4]), hashes: []uint64{1, 2, 1, 2}}
+		}},
+		{name: "Allower", packable: func() binpack.Packable {
+			return &testNowherePackable{testPackable: newTestPackable(rectangles[:2])}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: pack the Packable on its own as the reference.
+			expected, expectedErr := binpack.PackWithOptions(tt.packable(), tt.opts)
+			p, _ := binpack.Audited(tt.packable())
+
+			// Act: pack the audited Packable.
+			layout, err := binpack.PackWithOptions(p, tt.opts)
+
+			// Assert: the layouts and errors are identical.
+			require.Equal(t, expected, layout)
+			if expectedErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, expectedErr.Error())
+		})
+	}
+}
+
+// TestAudited_NotHasher verifies that the wrapper of a Packable that does not
+// implement Hasher packs every rectangle separately, even if some are equal.
+func TestAudited_NotHasher(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: three identical tiles without hashes.
+	p, check := binpack.Audited(newTestPackable([]binpack.Rectangle{{Width: 10, Height: 10}, {Width: 10, Height: 10}, {Width: 10, Height: 10}}))
+
+	// Act: pack the audited Packable.
+	layout, err := binpack.PackWithOptions(p, binpack.Options{})
+
+	// Assert: every tile has its own placement.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, 3)
+	require.Empty(t, layout.Refs)
+	require.NoError(t, check())
+}
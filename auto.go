@@ -0,0 +1,128 @@
+package binpack
+
+import (
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+const (
+	// autoExhaustiveLimit is the largest number of rectangles HeuristicAuto
+	// packs with the exhaustive search of PackOptimal. Six rectangles take
+	// around 10ms, while seven take hundreds of milliseconds.
+	autoExhaustiveLimit = 6
+
+	// autoShelfThreshold is the number of rectangles from which
+	// HeuristicAuto packs with shelves. The default heuristic takes over a
+	// second for 400 rectangles and grows faster than cubically beyond.
+	autoShelfThreshold = 500
+)
+
+// packAuto computes the layout for HeuristicAuto, choosing the algorithm by
+// the number of rectangles in p. The exhaustive search and the shelves only
+// support the size limits, so any other option or optional interface selects
+// the default heuristic. The exhaustive search stops at opts.Deadline with the
+// best layout found so far.
+func packAuto(p Packable, opts Options) (Layout, []int, []int) {
+	if opts.plain(p) {
+		var skip = skipper(p)
+		var count int
+		for i := 0; i < p.Len(); i++ {
+			if !skip(i) && p.Rectangle(i).area64() > 0 {
+				count++
+			}
+		}
+		switch {
+		case count <= autoExhaustiveLimit:
+			if layout, ok := packOptimal(p, opts.MaxWidth, opts.MaxHeight, opts.Deadline); ok {
+				for range count {
+					opts.Stats.countPlacement()
+				}
+				return layout, nil, nil
+			}
+		case count >= autoShelfThreshold:
+			return packShelf(p, opts)
+		}
+	}
+	return packAround(p, opts, nil, bounds{})
+}
+
+// plain returns true if the options only set the size limits and the
+// deadline, and p implements none of the optional interfaces that affect
+// packing.
+func (o Options) plain(p Packable) bool {
+	var optionals = []bool{
+		implements[Pinner](p), implements[ExclusiveGroup](p), implements[Aligner](p), implements[Centralizer](p),
+		implements[Hasher](p), implements[Flexible](p), implements[Backgrounder](p), implements[Allower](p),
+		implements[Mirrorer](p), implements[Rounder](p),
+	}
+	if slices.Contains(optionals, true) {
+		return false
+	}
+	var plain = Options{MaxWidth: o.MaxWidth, MaxHeight: o.MaxHeight, Heuristic: o.Heuristic, Stats: o.Stats, Deadline: o.Deadline}
+	return reflect.DeepEqual(o, plain)
+}
+
+// packShelf places the rectangles in p on shelves, tallest first, filling
+// each shelf from left to right before starting the next below it. The
+// shelves are as wide as MaxWidth, or wide enough to make the layout roughly
+// square if it is unbounded. Rectangles that do not fit within the limits are
+// left unplaced.
+func packShelf(p Packable, opts Options) (Layout, []int, []int) {
+	var skip = skipper(p)
+	var layout Layout
+	var positions []int
+	var area int64
+	var widest int
+	for i := 0; i < p.Len(); i++ {
+		if skip(i) {
+			continue
+		}
+		opts.Stats.countPlacement()
+		var r = p.Rectangle(i)
+		if r.area64() == 0 {
+			layout.Placements = append(layout.Placements, Placement{Index: i, Width: r.Width, Height: r.Height})
+			continue
+		}
+		positions = append(positions, i)
+		area += r.area64()
+		widest = max(widest, r.Width)
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		var a, b = p.Rectangle(positions[i]), p.Rectangle(positions[j])
+		if a.Height != b.Height {
+			return a.Height > b.Height
+		}
+		return a.Width > b.Width
+	})
+
+	var shelfWidth = opts.MaxWidth
+	if shelfWidth <= 0 {
+		shelfWidth = max(widest, int(math.Ceil(math.Sqrt(float64(area)))))
+	}
+
+	var unplaced []int
+	var x, y, shelfHeight int
+	for _, i := range positions {
+		var r = p.Rectangle(i)
+		if x > 0 && x+r.Width > shelfWidth {
+			x, y, shelfHeight = 0, y+shelfHeight, 0
+		}
+		if r.Width > shelfWidth || (opts.MaxHeight > 0 && y+r.Height > opts.MaxHeight) {
+			unplaced = append(unplaced, i)
+			continue
+		}
+		layout.Placements = append(layout.Placements, Placement{Index: i, X: x, Y: y, Width: r.Width, Height: r.Height})
+		layout.Width = max(layout.Width, x+r.Width)
+		layout.Height = max(layout.Height, y+r.Height)
+		x += r.Width
+		shelfHeight = max(shelfHeight, r.Height)
+	}
+
+	sort.Slice(layout.Placements, func(i, j int) bool {
+		return layout.Placements[i].Index < layout.Placements[j].Index
+	})
+	sort.Ints(unplaced)
+	return layout, unplaced, nil
+}
@@ -0,0 +1,21 @@
+package binpack
+
+// Backgrounder is an optional interface for Packables with layered
+// rectangles, such as the backdrops of a poster. Background rectangles are
+// placed before the others, without overlapping each other. The foreground
+// rectangles may then be placed on top of the backgrounds, but never overlap
+// each other. Layout.Backgrounds lists the backgrounds so they can be drawn
+// first.
+type Backgrounder interface {
+	// Background returns true if the rectangle at index n is a background.
+	Background(n int) bool
+}
+
+// backgrounder returns a function that reports whether the rectangle at index
+// n of p is a background.
+func backgrounder(p Packable) func(n int) bool {
+	if b, ok := optional[Backgrounder](p); ok {
+		return b.Background
+	}
+	return func(int) bool { return false }
+}
@@ -0,0 +1,51 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testBackgroundPackable extends testPackable with background rectangles.
+type testBackgroundPackable struct {
+	*testPackable
+	backgrounds map[int]bool
+}
+
+// Ensure that testBackgroundPackable implements the binpack.Backgrounder interface.
+var _ binpack.Backgrounder = (*testBackgroundPackable)(nil)
+
+// Background returns true if the rectangle at the specified index is a background.
+func (tp *testBackgroundPackable) Background(n int) bool {
+	return tp.backgrounds[n]
+}
+
+// TestBackgrounder verifies that backgrounds are placed first and that the
+// foreground sits on top of them without overlapping itself.
+func TestBackgrounder(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: a backdrop large enough to hold the foreground, listed last.
+	tp := &testBackgroundPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 40, Height: 40},
+			{Width: 40, Height: 40},
+			{Width: 30, Height: 30},
+			{Width: 100, Height: 100},
+		}),
+		backgrounds: map[int]bool{3: true},
+	}
+
+	// Act: pack in placement order.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{OrderBy: binpack.OrderPlacement})
+
+	// Assert: the foreground should lie within the backdrop.
+	require.NoError(t, err)
+	require.Equal(t, 100, layout.Width)
+	require.Equal(t, 100, layout.Height)
+	require.Equal(t, []int{3}, layout.Backgrounds)
+	require.Equal(t, 3, layout.Placements[0].Index)
+	foreground := binpack.Layout{Width: layout.Width, Height: layout.Height, Placements: layout.Placements[1:]}
+	requireNoOverlap(t, foreground)
+}
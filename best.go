@@ -0,0 +1,155 @@
+package binpack
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// defaultAttempts is the number of orderings tried by PackBest when
+// Options.Attempts is zero.
+const defaultAttempts = 8
+
+// packableSnapshot is a copy of the rectangles and gaps of a Packable, such
+// as to seed the search of PackOptimal.
+type packableSnapshot struct {
+	rectangleSlice
+	skipped map[int]bool
+}
+
+// Skip returns true if the rectangle at index n is a gap.
+func (s *packableSnapshot) Skip(n int) bool {
+	return s.skipped[n]
+}
+
+// attempt is the result of packing the rectangles in one ordering.
+type attempt struct {
+	seed      int64
+	layout    Layout
+	unplaced  int
+	occupancy float64
+}
+
+// better returns true if a is preferable to o.
+func (a attempt) better(o attempt) bool {
+	if a.unplaced != o.unplaced {
+		return a.unplaced < o.unplaced
+	}
+	if a.occupancy != o.occupancy {
+		return a.occupancy > o.occupancy
+	}
+	var perimeter, otherPerimeter = a.layout.Width + a.layout.Height, o.layout.Width + o.layout.Height
+	if perimeter != otherPerimeter {
+		return perimeter < otherPerimeter
+	}
+	return a.seed < o.seed
+}
+
+// PackBest packs the rectangles several times and places them using the
+// layout with the highest occupancy, which is returned along with that
+// occupancy. The first attempt places the rectangles in the order set by
+// opts, largest first by default, and each further attempt i places them in a random order seeded by
+// opts.Seed+i, so the result is reproducible for a given seed. Up to
+// opts.Parallelism attempts run concurrently. No attempts are started after
+// opts.Deadline, and the best of those already run is used.
+//
+// Every attempt packs p itself, so the optional interfaces of p, such as
+// Allower and Hasher, are honoured as by PackWithOptions. The methods of p
+// other than Place are called concurrently by the attempts, so they must be
+// safe for concurrent use.
+//
+// Attempts that place more rectangles are preferred, and ties in occupancy
+// are broken by the smallest perimeter and then the lowest seed. Rectangles
+// that cannot be placed within the limits are left unplaced, as with
+// PackBestEffort.
+func PackBest(p Packable, opts Options) (Layout, float64) {
+	defer opts.Stats.begin()()
+	opts.PinReport = nil
+	var attempts = opts.Attempts
+	if attempts <= 0 {
+		attempts = defaultAttempts
+	}
+	if opts.Heuristic == HeuristicGrid || opts.Heuristic == HeuristicFilmstrip {
+		// The grid and filmstrip ignore the order of the rectangles, so every attempt is identical.
+		attempts = 1
+	}
+	var parallelism = opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	// Run the attempts concurrently, bounded by the parallelism.
+	var results = make([]attempt, attempts)
+	var semaphore = make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i := range results {
+		semaphore <- struct{}{}
+		if i > 0 && opts.expired() {
+			<-semaphore
+			results = results[:i]
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i] = packAttempt(p, opts, opts.Seed+int64(i), i == 0)
+		}(i)
+	}
+	wg.Wait()
+
+	var best = results[0]
+	for _, result := range results[1:] {
+		if result.better(best) {
+			best = result
+		}
+	}
+
+	best.layout.apply(p)
+	return best.layout, best.occupancy
+}
+
+// packAttempt packs the rectangles in p without placing them. The rectangles
+// are placed in the order set by opts if sorted is true, and otherwise in a
+// random order drawn from seed.
+func packAttempt(p Packable, opts Options, seed int64, sorted bool) attempt {
+	if !sorted && p.Len() > 0 && opts.Heuristic != HeuristicGrid && opts.Heuristic != HeuristicFilmstrip {
+		// Shuffle the rectangles that survive the limit, so the limit still keeps the same ones.
+		var positions = sortPositions(p, opts)
+		var kept = len(positions)
+		if opts.Limit > 0 {
+			kept = min(kept, opts.Limit)
+		}
+		var r = rand.New(rand.NewSource(seed))
+		r.Shuffle(kept, func(i, j int) {
+			positions[i], positions[j] = positions[j], positions[i]
+		})
+
+		// Pack in the shuffled order through pack, so the optional interfaces apply as usual.
+		var order = make([]int, len(positions))
+		for rank, position := range positions {
+			order[position] = rank
+		}
+		opts.LessIndex = func(i, j int) bool { return order[i] < order[j] }
+	}
+	var layout, unplaced, dropped = pack(p, opts)
+
+	return attempt{
+		seed:      seed,
+		layout:    layout,
+		unplaced:  len(unplaced) + len(dropped),
+		occupancy: occupancy(layout),
+	}
+}
+
+// occupancy returns the fraction of the layout covered by its placements.
+func occupancy(l Layout) float64 {
+	if l.Width == 0 || l.Height == 0 {
+		return 0
+	}
+	var area int64
+	for _, p := range l.Placements {
+		area += int64(p.Width) * int64(p.Height)
+	}
+	return float64(area) / (float64(l.Width) * float64(l.Height))
+}
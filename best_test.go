@@ -0,0 +1,92 @@
+package binpack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// layoutOccupancy returns the fraction of the layout covered by its placements.
+func layoutOccupancy(layout binpack.Layout) float64 {
+	var area int
+	for _, p := range layout.Placements {
+		area += p.Width * p.Height
+	}
+	return float64(area) / float64(layout.Width*layout.Height)
+}
+
+// TestPackBest_AtLeastPack verifies that the best layout is never worse than
+// the layout produced by Pack.
+func TestPackBest_AtLeastPack(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles of varying sizes.
+	rectangles := randomRectangles(15)
+	baseline, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+
+	// Act: pack the rectangles with several attempts.
+	tp := newTestPackable(rectangles)
+	layout, occupancy := binpack.PackBest(tp, binpack.Options{Attempts: 6, Seed: 42, Parallelism: 3})
+
+	// Assert: the occupancy should be reported and at least that of Pack.
+	require.InDelta(t, layoutOccupancy(layout), occupancy, 1e-9)
+	require.GreaterOrEqual(t, occupancy, layoutOccupancy(baseline))
+	require.Len(t, layout.Placements, len(rectangles))
+	requireNoOverlap(t, layout)
+
+	// Assert: the placements should be reported to the packable.
+	for _, p := range layout.Placements {
+		require.Equal(t, p.X, tp.placements[p.Index].x, "expected x-coordinate for rectangle %d", p.Index)
+		require.Equal(t, p.Y, tp.placements[p.Index].y, "expected y-coordinate for rectangle %d", p.Index)
+	}
+}
+
+// TestPackBest_Deterministic verifies that the same seed produces the same
+// layout regardless of parallelism.
+func TestPackBest_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles of varying sizes.
+	rectangles := randomRectangles(12)
+
+	// Act: pack the rectangles twice with the same seed.
+	first, _ := binpack.PackBest(newTestPackable(rectangles), binpack.Options{Attempts: 5, Seed: 7, Parallelism: 1})
+	second, _ := binpack.PackBest(newTestPackable(rectangles), binpack.Options{Attempts: 5, Seed: 7, Parallelism: 4})
+
+	// Assert: the layouts should be identical.
+	require.Equal(t, first, second)
+}
+
+// TestPackBest_Deadline verifies that no further attempts are made once the
+// deadline has passed, and the first attempt is still used.
+func TestPackBest_Deadline(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles of varying sizes.
+	rectangles := randomRectangles(12)
+	first, _ := binpack.PackBest(newTestPackable(rectangles), binpack.Options{Attempts: 1})
+
+	// Act: pack the rectangles with a deadline that has already passed.
+	tp := newTestPackable(rectangles)
+	layout, occupancy := binpack.PackBest(tp, binpack.Options{Attempts: 50, Seed: 3, Parallelism: 1, Deadline: time.Now().Add(-time.Second)})
+
+	// Assert: the layout should be that of the first attempt.
+	require.Equal(t, first, layout)
+	require.InDelta(t, layoutOccupancy(layout), occupancy, 1e-9)
+	requireNoOverlap(t, layout)
+}
+
+// TestPackBest_Empty verifies that an empty Packable produces an empty layout.
+func TestPackBest_Empty(t *testing.T) {
+	t.Parallel()
+
+	// Act: pack no rectangles.
+	layout, occupancy := binpack.PackBest(newTestPackable(nil), binpack.Options{})
+
+	// Assert: the layout should be empty.
+	require.Empty(t, layout.Placements)
+	require.Zero(t, occupancy)
+}
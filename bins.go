@@ -0,0 +1,153 @@
+package binpack
+
+import "slices"
+
+// minimizeBinsLimit is the largest number of rectangles for which
+// MinimizeBins searches the assignments of rectangles to bins. The number of
+// assignments grows faster than exponentially, and nine rectangles can
+// already be split in over 20000 ways.
+const minimizeBinsLimit = 8
+
+// PackBins packs the rectangles in p into bins limited by opts.MaxWidth and
+// opts.MaxHeight, or sized by opts.BinSizes, such as the pages of a texture
+// atlas, and returns the layout of each bin. Every rectangle is placed at its
+// position within its bin, and the Placements of each layout tell which
+// rectangles it holds. Bins are filled greedily: each holds as many of the
+// remaining rectangles as fit before the next is opened. If opts.MinimizeBins
+// is set and there are few rectangles, the assignments of rectangles to bins
+// are searched for the fewest bins instead, unless BinSizes is set. If a
+// rectangle does not fit in an empty bin of any size, ErrTooLarge is returned
+// and no rectangles are placed. Gaps of a Skipper are left out, and the other
+// optional interfaces are ignored.
+func PackBins(p Packable, opts Options) ([]Layout, error) {
+	defer opts.Stats.begin()()
+	var skip = skipper(p)
+	var sizes = make(rectangleSlice, p.Len())
+	var remaining []int
+	for i := range sizes {
+		sizes[i] = p.Rectangle(i)
+		if !skip(i) {
+			remaining = append(remaining, i)
+		}
+	}
+
+	// Fill the bins greedily, which also bounds the search for fewer bins.
+	// A size too small for any of the remaining rectangles is passed over.
+	var bins []Layout
+	for size := 0; len(remaining) > 0; size++ {
+		var last = size >= len(opts.BinSizes)-1
+		var layout, leftover = packBin(sizes, opts.bin(size), remaining)
+		if len(layout.Placements) == 0 {
+			if last {
+				return nil, ErrTooLarge
+			}
+			continue
+		}
+		bins = append(bins, layout)
+		remaining = leftover
+	}
+	if opts.MinimizeBins && len(opts.BinSizes) == 0 && len(bins) > 1 && sizes.count(skip) <= minimizeBinsLimit {
+		bins = minimizeBins(sizes, opts, skip, bins)
+	}
+
+	for _, layout := range bins {
+		layout.apply(p)
+	}
+	return bins, nil
+}
+
+// bin returns the options for the bin opened after the given number of bin
+// sizes have been used, limited by the size in BinSizes. The last size is
+// used for every bin beyond the end of BinSizes.
+func (o Options) bin(n int) Options {
+	if len(o.BinSizes) > 0 {
+		var size = o.BinSizes[min(n, len(o.BinSizes)-1)]
+		o.MaxWidth, o.MaxHeight = size.Width, size.Height
+	}
+	return o
+}
+
+// count returns the number of rectangles in s that are not skipped.
+func (s rectangleSlice) count(skip func(n int) bool) int {
+	var count int
+	for i := range s {
+		if !skip(i) {
+			count++
+		}
+	}
+	return count
+}
+
+// packBin packs as many of the members of s as fit into a single bin.
+// Returns the layout of the bin and the members left over, in ascending
+// order.
+func packBin(s rectangleSlice, opts Options, members []int) (Layout, []int) {
+	var positions = slices.DeleteFunc(sortPositions(s, opts), func(n int) bool {
+		return !slices.Contains(members, n)
+	})
+	var layout, unplaced, dropped = packOrdered(s, opts, positions, nil, bounds{})
+	var leftover = append(unplaced, dropped...)
+	slices.Sort(leftover)
+	return layout, leftover
+}
+
+// minimizeBins searches the assignments of the rectangles in s to bins for
+// one that uses fewer bins than best, largest rectangles first, and returns
+// the layouts of the bins it finds. Each rectangle joins one of the open bins
+// or opens a new one, and a bin is kept only if packing confirms its
+// rectangles fit. The search ends early once the bins hold no more than the
+// area of the rectangles requires.
+func minimizeBins(s rectangleSlice, opts Options, skip func(n int) bool, best []Layout) []Layout {
+	var positions = slices.DeleteFunc(sortPositions(s, opts), skip)
+
+	// No assignment can use fewer bins than the total area needs.
+	var fewest = 1
+	if opts.MaxWidth > 0 && opts.MaxHeight > 0 {
+		var area int64
+		for _, position := range positions {
+			area += s[position].area64()
+		}
+		var binArea = int64(opts.MaxWidth) * int64(opts.MaxHeight)
+		fewest = max(fewest, int((area+binArea-1)/binArea))
+	}
+
+	var members [][]int
+	var layouts []Layout
+	var search func(k int)
+	search = func(k int) {
+		if len(best) <= fewest {
+			return
+		}
+		if k == len(positions) {
+			if len(layouts) < len(best) {
+				best = slices.Clone(layouts)
+			}
+			return
+		}
+
+		var position = positions[k]
+		for j := range members {
+			var layout, leftover = packBin(s, opts, append(slices.Clone(members[j]), position))
+			if len(leftover) > 0 {
+				continue
+			}
+			var previous = layouts[j]
+			members[j], layouts[j] = append(members[j], position), layout
+			search(k + 1)
+			members[j], layouts[j] = members[j][:len(members[j])-1], previous
+		}
+
+		// Only open another bin if it could still beat the best so far.
+		if len(members)+1 < len(best) {
+			var layout, leftover = packBin(s, opts, []int{position})
+			if len(leftover) > 0 {
+				return
+			}
+			members, layouts = append(members, []int{position}), append(layouts, layout)
+			search(k + 1)
+			members, layouts = members[:len(members)-1], layouts[:len(layouts)-1]
+		}
+	}
+	search(0)
+	return best
+}
@@ -0,0 +1,113 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackBins verifies that rectangles are spread over as many bins as they
+// need, each within the limits.
+func TestPackBins(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: thirty random rectangles.
+	rectangles := randomRectangles(30)
+	tp := newTestPackable(rectangles)
+
+	// Act: pack them into bins of 256x256.
+	bins, err := binpack.PackBins(tp, binpack.Options{MaxWidth: 256, MaxHeight: 256})
+
+	// Assert: every rectangle should be placed in exactly one bin.
+	require.NoError(t, err)
+	require.Greater(t, len(bins), 1)
+	var seen = make(map[int]bool)
+	for _, bin := range bins {
+		require.LessOrEqual(t, bin.Width, 256)
+		require.LessOrEqual(t, bin.Height, 256)
+		requireNoOverlap(t, bin)
+		for _, p := range bin.Placements {
+			require.False(t, seen[p.Index], "rectangle %d placed twice", p.Index)
+			seen[p.Index] = true
+			require.Equal(t, p.X, tp.placements[p.Index].x)
+			require.Equal(t, p.Y, tp.placements[p.Index].y)
+		}
+	}
+	require.Len(t, seen, len(rectangles))
+}
+
+// TestPackBins_MinimizeBins verifies that MinimizeBins finds a split into
+// fewer bins than filling them greedily.
+func TestPackBins_MinimizeBins(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: greedily, 5 and 4 share a bin and leave the 2 without room.
+	rectangles := []binpack.Rectangle{
+		{Width: 5, Height: 1},
+		{Width: 4, Height: 1},
+		{Width: 3, Height: 1},
+		{Width: 3, Height: 1},
+		{Width: 3, Height: 1},
+		{Width: 2, Height: 1},
+	}
+
+	// Act: pack them greedily and with the fewest bins.
+	greedy, err := binpack.PackBins(newTestPackable(rectangles), binpack.Options{MaxWidth: 10, MaxHeight: 1})
+	require.NoError(t, err)
+	minimized, err := binpack.PackBins(newTestPackable(rectangles), binpack.Options{MaxWidth: 10, MaxHeight: 1, MinimizeBins: true})
+	require.NoError(t, err)
+
+	// Assert: the search saves a bin and places every rectangle.
+	require.Len(t, greedy, 3)
+	require.Len(t, minimized, 2)
+	require.Len(t, append(minimized[0].Placements, minimized[1].Placements...), len(rectangles))
+}
+
+// TestPackBins_TooLarge verifies that nothing is placed when a rectangle does
+// not fit in an empty bin.
+func TestPackBins_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: the second rectangle is too wide for the bin.
+	tp := newTestPackable([]binpack.Rectangle{{Width: 10, Height: 10}, {Width: 20, Height: 10}})
+	tp.placements[0].x = -1
+
+	// Act: pack them into bins.
+	bins, err := binpack.PackBins(tp, binpack.Options{MaxWidth: 15, MaxHeight: 15})
+
+	// Assert: the error is reported and nothing is placed.
+	require.ErrorIs(t, err, binpack.ErrTooLarge)
+	require.Nil(t, bins)
+	require.Equal(t, -1, tp.placements[0].x)
+}
+
+// TestPackBins_BinSizes verifies that bins are opened with the sizes in turn,
+// reusing the last, and that a rectangle too large for every size is an
+// error.
+func TestPackBins_BinSizes(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: a small and a large bin size, and tiles for each.
+	sizes := []binpack.Rectangle{{Width: 10, Height: 10}, {Width: 20, Height: 20}}
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 15, Height: 15},
+		{Width: 10, Height: 10},
+		{Width: 20, Height: 20},
+	})
+
+	// Act: pack the tiles, and a rectangle too wide for every size.
+	bins, err := binpack.PackBins(tp, binpack.Options{BinSizes: sizes})
+	_, tooLarge := binpack.PackBins(newTestPackable([]binpack.Rectangle{{Width: 30, Height: 5}}), binpack.Options{BinSizes: sizes})
+
+	// Assert: the small bin takes the tile, and the large size is reused.
+	require.NoError(t, err)
+	require.Len(t, bins, 3)
+	require.Equal(t, []binpack.Placement{{Index: 1, Width: 10, Height: 10}}, bins[0].Placements)
+	for _, bin := range bins[1:] {
+		require.LessOrEqual(t, bin.Width, 20)
+		require.LessOrEqual(t, bin.Height, 20)
+		require.Len(t, bin.Placements, 1)
+	}
+	require.ErrorIs(t, tooLarge, binpack.ErrTooLarge)
+}
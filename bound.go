@@ -0,0 +1,56 @@
+package binpack
+
+import "math"
+
+// LowerBound returns the dimensions of the smallest near-square box that
+// could hold rects if they packed perfectly. The width is at least that of
+// the widest rectangle, the height is at least that of the tallest, and their
+// product is at least the total area of the rectangles. Comparing the
+// dimensions of a layout against it quantifies packing efficiency.
+func LowerBound(rects []Rectangle) (minWidth, minHeight int) {
+	var area int
+	for _, r := range rects {
+		area += r.Area()
+		minWidth = max(minWidth, r.Width)
+		minHeight = max(minHeight, r.Height)
+	}
+	if area == 0 {
+		return minWidth, minHeight
+	}
+
+	// Grow the width toward a square, then find the height needed to hold the area.
+	minWidth = max(minWidth, int(math.Ceil(math.Sqrt(float64(area)))))
+	minHeight = max(minHeight, (area+minWidth-1)/minWidth)
+	return minWidth, minHeight
+}
+
+// TotalArea returns the combined area of rects. The sum is computed in 64
+// bits so that large sets of rectangles do not overflow.
+func TotalArea(rects []Rectangle) int64 {
+	var area int64
+	for _, r := range rects {
+		area += r.area64()
+	}
+	return area
+}
+
+// FitsByArea returns true if the combined area of rects does not exceed the
+// area of a binW x binH bin. It is a cheap necessary condition: rectangles
+// that fail it can never be packed into the bin, but passing it does not
+// guarantee that they can.
+func FitsByArea(rects []Rectangle, binW, binH int) bool {
+	return TotalArea(rects) <= int64(binW)*int64(binH)
+}
+
+// SmallestFittingBin returns the smallest side in allowed of a square bin that
+// holds the layout, such as the smallest supported texture size, or false if
+// none of them are large enough. The sides in allowed may be in any order.
+func SmallestFittingBin(layout Layout, allowed []int) (int, bool) {
+	var best, found = 0, false
+	for _, side := range allowed {
+		if side >= layout.Width && side >= layout.Height && (!found || side < best) {
+			best, found = side, true
+		}
+	}
+	return best, found
+}
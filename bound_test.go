@@ -0,0 +1,110 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLowerBound_Empty verifies that no rectangles have a zero bound.
+func TestLowerBound_Empty(t *testing.T) {
+	t.Parallel()
+
+	// Act: compute the bound of no rectangles.
+	w, h := binpack.LowerBound(nil)
+
+	// Assert: the bound should be (0, 0).
+	require.Equal(t, 0, w, "expected width 0")
+	require.Equal(t, 0, h, "expected height 0")
+}
+
+// TestLowerBound_Square verifies that equal tiles produce a square bound.
+func TestLowerBound_Square(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create four equal tiles.
+	rectangles := []binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+	}
+
+	// Act: compute the bound.
+	w, h := binpack.LowerBound(rectangles)
+
+	// Assert: the bound should be the perfect 20x20 square.
+	require.Equal(t, 20, w, "expected width 20")
+	require.Equal(t, 20, h, "expected height 20")
+}
+
+// TestLowerBound_WideRectangle verifies that the bound is never narrower than
+// the widest rectangle.
+func TestLowerBound_WideRectangle(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a wide rectangle and a small one.
+	rectangles := []binpack.Rectangle{
+		{Width: 100, Height: 10},
+		{Width: 10, Height: 10},
+	}
+
+	// Act: compute the bound.
+	w, h := binpack.LowerBound(rectangles)
+
+	// Assert: the width should match the wide rectangle and the height should hold the area.
+	require.Equal(t, 100, w, "expected width 100")
+	require.Equal(t, 11, h, "expected height 11")
+}
+
+// TestTotalArea verifies that the combined area is summed without overflow.
+func TestTotalArea(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles whose combined area exceeds 32 bits.
+	rectangles := []binpack.Rectangle{
+		{Width: 100000, Height: 100000},
+		{Width: 10, Height: 20},
+	}
+
+	// Act: compute the total area.
+	area := binpack.TotalArea(rectangles)
+
+	// Assert: the area should be the exact sum.
+	require.Equal(t, int64(10000000200), area)
+}
+
+// TestFitsByArea verifies that rectangles are compared against the bin area.
+func TestFitsByArea(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles with a combined area of 5000.
+	rectangles := []binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 50, Height: 50},
+	}
+
+	// Act & Assert: the rectangles should fit a bin of equal or greater area only.
+	require.True(t, binpack.FitsByArea(rectangles, 100, 50))
+	require.True(t, binpack.FitsByArea(rectangles, 100, 100))
+	require.False(t, binpack.FitsByArea(rectangles, 99, 50))
+}
+
+// TestSmallestFittingBin verifies that the smallest square bin holding the
+// layout is chosen from the allowed sizes.
+func TestSmallestFittingBin(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout that is wider than it is tall.
+	layout := binpack.Layout{Width: 600, Height: 200}
+
+	// Act: choose from sizes that are and are not large enough.
+	side, ok := binpack.SmallestFittingBin(layout, []int{2048, 256, 1024, 512})
+	_, tooSmall := binpack.SmallestFittingBin(layout, []int{256, 512})
+
+	// Assert: the bin must be at least as large as the wider dimension.
+	require.True(t, ok)
+	require.Equal(t, 1024, side)
+	require.False(t, tooSmall)
+}
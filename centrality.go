@@ -0,0 +1,17 @@
+package binpack
+
+// Centralizer is an optional interface for Packables whose rectangles vary in
+// importance, such as photos in a collage ranked by saliency. The more central
+// a rectangle, the harder it is pulled toward the center of the layout. When
+// Options.CenterIndex is enabled, every rectangle is already placed as close
+// to the center rectangle as it can be, so centrality has no further effect.
+type Centralizer interface {
+	// Centrality returns a value from 0 to 1 for the rectangle at index n.
+	// Zero leaves the rectangle to the score alone, and higher values add the
+	// squared distance between the rectangle and the center of the bounding
+	// box, multiplied by the centrality, to its score. The distance is
+	// weighted by the centrality rather than by one minus it, as weighting it
+	// by one minus the centrality would pull the least important rectangles
+	// hardest. Values outside the range are clamped.
+	Centrality(n int) float64
+}
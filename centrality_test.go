@@ -0,0 +1,61 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testCentralizerPackable extends testPackable with the centrality of each
+// rectangle.
+type testCentralizerPackable struct {
+	*testPackable
+	centrality map[int]float64
+}
+
+// Ensure that testCentralizerPackable implements the binpack.Centralizer interface.
+var _ binpack.Centralizer = (*testCentralizerPackable)(nil)
+
+// Centrality returns the centrality of the rectangle at the specified index.
+func (tc *testCentralizerPackable) Centrality(n int) float64 {
+	return tc.centrality[n]
+}
+
+// TestCentralizer verifies that a central rectangle gives up some area to sit
+// nearer the middle of the layout.
+func TestCentralizer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		centrality float64
+		x, y       int
+	}{
+		{name: "Default", centrality: 0, x: 0, y: 60},
+		{name: "Central", centrality: 1, x: 30, y: 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: the small rectangle fits most cheaply at the end of the column.
+			tc := &testCentralizerPackable{
+				testPackable: newTestPackable([]binpack.Rectangle{
+					{Width: 30, Height: 30},
+					{Width: 30, Height: 30},
+					{Width: 10, Height: 10},
+				}),
+				centrality: map[int]float64{2: tt.centrality},
+			}
+
+			// Act: pack with the centrality.
+			layout, err := binpack.PackWithOptions(tc, binpack.Options{})
+
+			// Assert: the small rectangle is placed where its centrality leads it.
+			require.NoError(t, err)
+			require.Equal(t, tt.x, layout.Placements[2].X)
+			require.Equal(t, tt.y, layout.Placements[2].Y)
+		})
+	}
+}
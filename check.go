@@ -0,0 +1,195 @@
+package binpack
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+)
+
+// ErrInvalidLayout is returned by ValidateLayout when a layout does not match
+// the rectangles of a Packable.
+var ErrInvalidLayout = errors.New("binpack: layout does not match the rectangles")
+
+// CheckOverlaps returns the largest area shared by any two rectangles in p
+// when the rectangle at each index n is positioned at placements[n], or zero
+// if no rectangles overlap. Rectangles with no area, skipped rectangles, and
+// rectangles positioned at (Unplaced, Unplaced) or beyond the end of
+// placements are ignored. It is intended for validating layouts, such as in
+// fuzz tests.
+func CheckOverlaps(p Packable, placements []image.Point) int {
+	var skip = skipper(p)
+	var unplaced = image.Pt(Unplaced, Unplaced)
+
+	// Collect the rectangles that occupy space, sorted by their left edge.
+	var rects []image.Rectangle
+	for i := 0; i < min(p.Len(), len(placements)); i++ {
+		var r = p.Rectangle(i)
+		if r.area64() == 0 || skip(i) || placements[i] == unplaced {
+			continue
+		}
+		rects = append(rects, image.Rectangle{Min: placements[i], Max: placements[i].Add(image.Pt(r.Width, r.Height))})
+	}
+	sort.Slice(rects, func(i, j int) bool {
+		return rects[i].Min.X < rects[j].Min.X
+	})
+
+	// Sweep from left to right, comparing each rectangle with those that start before its right edge.
+	var largest int
+	for i, a := range rects {
+		for _, b := range rects[i+1:] {
+			if b.Min.X >= a.Max.X {
+				break
+			}
+			var overlap = a.Intersect(b)
+			largest = max(largest, overlap.Dx()*overlap.Dy())
+		}
+	}
+	return largest
+}
+
+// ValidateLayout checks that l is a valid layout of the rectangles in p, such
+// as before applying a cached layout to inputs that may have changed. Every
+// rectangle must have exactly one placement, or a reference in l.Refs to a
+// placed rectangle, unless it is skipped or lost to another member of its
+// exclusive group. Each placement must have the dimensions of its rectangle
+// in either orientation, or a size within the range of a Flexible, lie
+// within l.Width and l.Height, and not overlap another, except for the pairs
+// listed in l.Overlaps and the backgrounds in l.Backgrounds, or where
+// rounded corners of a Rounder leave room. Returns ErrInvalidLayout
+// describing the first problem found. Use ValidateLayoutWithOptions for
+// layouts packed with options that move the rectangles off the canvas.
+func ValidateLayout(p Packable, l Layout) error {
+	return ValidateLayoutWithOptions(p, l, Options{})
+}
+
+// ValidateLayoutWithOptions checks that l is a valid layout of the rectangles
+// in p packed with opts, as ValidateLayout does. With Relative or
+// KeepPinPositions, whose placements can lie at negative positions, the
+// placements must instead fit within l.Width and l.Height measured from
+// their top-left corner. With HeuristicGrid, rectangles may also be scaled
+// down to fit their cells, keeping their proportions.
+func ValidateLayoutWithOptions(p Packable, l Layout, opts Options) error {
+	// Measure the bounds from the corner of the placements if the layout may have been moved.
+	var originX, originY int
+	if opts.Relative || opts.KeepPinPositions {
+		originX, originY = math.MaxInt, math.MaxInt
+		for _, placement := range l.Placements {
+			if placement.Width > 0 && placement.Height > 0 {
+				originX, originY = min(originX, placement.X), min(originY, placement.Y)
+			}
+		}
+	}
+
+	var count = p.Len()
+	var flexible, _ = optional[Flexible](p)
+	var placed = make(map[int]bool, len(l.Placements))
+	for _, placement := range l.Placements {
+		var n = placement.Index
+		var x, y = placement.X - originX, placement.Y - originY
+		switch {
+		case n < 0 || n >= count:
+			return fmt.Errorf("%w: placement of rectangle %d, want fewer than %d rectangles", ErrInvalidLayout, n, count)
+		case placed[n]:
+			return fmt.Errorf("%w: rectangle %d is placed more than once", ErrInvalidLayout, n)
+		case resized(p.Rectangle(n), placement) && (flexible == nil || !withinSizeRange(flexible, placement)) && (opts.Heuristic != HeuristicGrid || !scaledDown(p.Rectangle(n), placement)):
+			var r = p.Rectangle(n)
+			return fmt.Errorf("%w: rectangle %d is placed as %dx%d, want %dx%d", ErrInvalidLayout, n, placement.Width, placement.Height, r.Width, r.Height)
+		case placement.Width > 0 && placement.Height > 0 && (x < 0 || y < 0 || x+placement.Width > l.Width || y+placement.Height > l.Height):
+			return fmt.Errorf("%w: rectangle %d at (%d, %d) lies outside the %dx%d layout", ErrInvalidLayout, n, placement.X, placement.Y, l.Width, l.Height)
+		}
+		placed[n] = true
+	}
+	for _, ref := range l.Refs {
+		switch {
+		case ref.Index < 0 || ref.Index >= count:
+			return fmt.Errorf("%w: reference of rectangle %d, want fewer than %d rectangles", ErrInvalidLayout, ref.Index, count)
+		case placed[ref.Index]:
+			return fmt.Errorf("%w: rectangle %d is placed more than once", ErrInvalidLayout, ref.Index)
+		case ref.Ref < 0 || ref.Ref >= count || !placed[ref.Ref]:
+			return fmt.Errorf("%w: rectangle %d refers to rectangle %d, which is not placed", ErrInvalidLayout, ref.Index, ref.Ref)
+		}
+	}
+	for _, ref := range l.Refs {
+		placed[ref.Index] = true
+	}
+
+	// A rectangle may be missing if it is a gap, or another member of its exclusive group is placed.
+	var skip = skipper(p)
+	var groups = make(map[int]bool)
+	var g, grouped = optional[ExclusiveGroup](p)
+	if grouped {
+		for n := range placed {
+			if group, ok := g.Group(n); ok {
+				groups[group] = true
+			}
+		}
+	}
+	for n := 0; n < count; n++ {
+		if placed[n] || skip(n) {
+			continue
+		}
+		if grouped {
+			if group, ok := g.Group(n); ok && groups[group] {
+				continue
+			}
+		}
+		return fmt.Errorf("%w: rectangle %d is not placed", ErrInvalidLayout, n)
+	}
+
+	// Sweep from left to right, comparing each placement with those that start before its right edge.
+	var allowed = make(map[[2]int]bool, len(l.Overlaps))
+	for _, pair := range l.Overlaps {
+		allowed[[2]int{min(pair.A, pair.B), max(pair.A, pair.B)}] = true
+	}
+	var radii = cornerRadii(p)
+	var backgrounds = make(map[int]bool, len(l.Backgrounds))
+	for _, n := range l.Backgrounds {
+		backgrounds[n] = true
+	}
+	var placements = make([]placement, 0, len(l.Placements))
+	for _, placement := range l.Placements {
+		if placement.Width > 0 && placement.Height > 0 && !backgrounds[placement.Index] {
+			placements = append(placements, newPlacement(placement))
+		}
+	}
+	sort.Slice(placements, func(i, j int) bool {
+		return placements[i].x < placements[j].x
+	})
+	for i, a := range placements {
+		for _, b := range placements[i+1:] {
+			if b.x >= a.x+a.width {
+				break
+			}
+			if radii != nil && !roundedIntersect(a, b, radii(a.position), radii(b.position)) {
+				continue
+			}
+			if doRectanglesIntersect(a, b) && !allowed[[2]int{min(a.position, b.position), max(a.position, b.position)}] {
+				return fmt.Errorf("%w: rectangles %d and %d overlap", ErrInvalidLayout, min(a.position, b.position), max(a.position, b.position))
+			}
+		}
+	}
+	return nil
+}
+
+// withinSizeRange reports whether the placement has a size in the range of
+// its rectangle in either orientation.
+func withinSizeRange(f Flexible, p Placement) bool {
+	var smallest, preferred = f.SizeRange(p.Index)
+	var within = func(w, h int) bool {
+		return w >= smallest.Width && w <= preferred.Width && h >= smallest.Height && h <= preferred.Height
+	}
+	return within(p.Width, p.Height) || within(p.Height, p.Width)
+}
+
+// scaledDown reports whether the placement has the size of r scaled down,
+// keeping its proportions to within the rounding of each dimension, in either
+// orientation.
+func scaledDown(r Rectangle, p Placement) bool {
+	var proportional = func(w, h int) bool {
+		var skew = int64(w)*int64(r.Height) - int64(h)*int64(r.Width)
+		return w <= r.Width && h <= r.Height && 2*max(skew, -skew) <= int64(r.Width)+int64(r.Height)
+	}
+	return proportional(p.Width, p.Height) || proportional(p.Height, p.Width)
+}
@@ -0,0 +1,275 @@
+package binpack_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckOverlaps verifies that the largest overlap between any two
+// rectangles is reported.
+func TestCheckOverlaps(t *testing.T) {
+	t.Parallel()
+
+	rectangles := []binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 20, Height: 5},
+	}
+	tests := []struct {
+		name       string
+		placements []image.Point
+		want       int
+	}{
+		{name: "Disjoint", placements: []image.Point{{0, 0}, {10, 0}, {0, 10}}, want: 0},
+		{name: "Overlapping", placements: []image.Point{{0, 0}, {5, 5}, {0, 8}}, want: 50},
+		{name: "Unplaced", placements: []image.Point{{0, 0}, {binpack.Unplaced, binpack.Unplaced}, {0, 10}}, want: 0},
+		{name: "Missing", placements: []image.Point{{0, 0}, {0, 0}}, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: check the placements for overlaps.
+			overlap := binpack.CheckOverlaps(newTestPackable(rectangles), tt.placements)
+
+			// Assert: the largest overlap should be reported.
+			require.Equal(t, tt.want, overlap)
+		})
+	}
+}
+
+// TestCheckOverlaps_Pack verifies that packed layouts have no overlaps.
+func TestCheckOverlaps_Pack(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pack a set of random rectangles.
+	tp := newTestPackable(randomRectangles(50))
+	binpack.Pack(tp)
+
+	// Act: check the resulting positions for overlaps.
+	placements := make([]image.Point, len(tp.placements))
+	for i, placement := range tp.placements {
+		placements[i] = image.Pt(placement.x, placement.y)
+	}
+	overlap := binpack.CheckOverlaps(tp, placements)
+
+	// Assert: no rectangles should overlap.
+	require.Zero(t, overlap)
+}
+
+// TestValidateLayout verifies that layouts that do not match the rectangles
+// are rejected with the problem found.
+func TestValidateLayout(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pack a set of random rectangles, and a valid layout by hand.
+	rectangles := randomRectangles(30)
+	packed, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 20, Height: 10},
+	})
+	valid := func() binpack.Layout {
+		return binpack.Layout{
+			Width: 30, Height: 10,
+			Placements: []binpack.Placement{
+				{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+				{Index: 1, X: 10, Y: 0, Width: 20, Height: 10},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		layout func() binpack.Layout
+		err    string
+	}{
+		{
+			name:   "Valid",
+			layout: valid,
+		},
+		{
+			name: "Rotated",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Height = 20
+				l.Placements[1].Width, l.Placements[1].Height = 10, 20
+				return l
+			},
+		},
+		{
+			name: "Missing",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Placements = l.Placements[:1]
+				return l
+			},
+			err: "rectangle 1 is not placed",
+		},
+		{
+			name: "Duplicate",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Placements[1].Index = 0
+				return l
+			},
+			err: "rectangle 0 is placed more than once",
+		},
+		{
+			name: "Stale",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Placements = append(l.Placements, binpack.Placement{Index: 2})
+				return l
+			},
+			err: "placement of rectangle 2, want fewer than 2 rectangles",
+		},
+		{
+			name: "Resized",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Placements[1].Width = 15
+				return l
+			},
+			err: "rectangle 1 is placed as 15x10, want 20x10",
+		},
+		{
+			name: "OutOfBounds",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Width = 25
+				return l
+			},
+			err: "rectangle 1 at (10, 0) lies outside the 25x10 layout",
+		},
+		{
+			name: "Overlap",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Placements[1].X = 5
+				return l
+			},
+			err: "rectangles 0 and 1 overlap",
+		},
+		{
+			name: "AllowedOverlap",
+			layout: func() binpack.Layout {
+				l := valid()
+				l.Placements[1].X = 5
+				l.Overlaps = []binpack.OverlapPair{{A: 0, B: 1, Area: 50}}
+				return l
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: validate the layout.
+			err := binpack.ValidateLayout(tp, tt.layout())
+
+			// Assert: only the broken layouts are invalid, with the reason given.
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, binpack.ErrInvalidLayout)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	// Assert: packed layouts are valid.
+	require.NoError(t, binpack.ValidateLayout(newTestPackable(rectangles), packed))
+	require.ErrorIs(t, binpack.ValidateLayout(newTestPackable(rectangles[1:]), packed), binpack.ErrInvalidLayout)
+}
+
+// TestValidateLayoutWithOptions verifies that layouts packed with options
+// that move or resize the rectangles are valid against those options.
+func TestValidateLayoutWithOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		p     func() binpack.Packable
+		opts  binpack.Options
+		moved bool
+	}{
+		{
+			name:  "Relative",
+			p:     func() binpack.Packable { return newTestPackable(randomRectangles(20)) },
+			opts:  binpack.Options{Relative: true, RelativeTo: 10},
+			moved: true,
+		},
+		{
+			name: "KeepPinPositions",
+			p: func() binpack.Packable {
+				return &testPinPackable{
+					testPackable: newTestPackable([]binpack.Rectangle{{Width: 10, Height: 10}, {Width: 20, Height: 20}}),
+					pins:         map[int]image.Point{0: {X: -30, Y: -20}},
+				}
+			},
+			opts:  binpack.Options{KeepPinPositions: true},
+			moved: true,
+		},
+		{
+			name: "Flexible",
+			p: func() binpack.Packable {
+				return &testFlexiblePackable{
+					testPackable: newTestPackable([]binpack.Rectangle{{Width: 100, Height: 60}, {Width: 100, Height: 60}}),
+					sizes:        make(map[int]binpack.Rectangle),
+				}
+			},
+			opts: binpack.Options{MaxWidth: 100, MaxHeight: 100},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: pack the Packable with the options.
+			p := tt.p()
+			layout, err := binpack.PackWithOptions(p, tt.opts)
+			require.NoError(t, err)
+
+			// Act: validate the layout against the same options.
+			err = binpack.ValidateLayoutWithOptions(p, layout, tt.opts)
+
+			// Assert: the layout is valid with its options, and moved layouts are not without them.
+			require.NoError(t, err)
+			if tt.moved {
+				require.ErrorIs(t, binpack.ValidateLayout(p, layout), binpack.ErrInvalidLayout)
+			} else {
+				require.NoError(t, binpack.ValidateLayout(p, layout))
+			}
+		})
+	}
+}
+
+// TestOverlap verifies that rectangles overlap only when they share area.
+func TestOverlap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b image.Rectangle
+		want bool
+	}{
+		{name: "Overlapping", a: image.Rect(0, 0, 10, 10), b: image.Rect(5, 5, 15, 15), want: true},
+		{name: "Contained", a: image.Rect(0, 0, 10, 10), b: image.Rect(2, 2, 4, 4), want: true},
+		{name: "Touching", a: image.Rect(0, 0, 10, 10), b: image.Rect(10, 0, 20, 10), want: false},
+		{name: "Apart", a: image.Rect(0, 0, 10, 10), b: image.Rect(0, 20, 10, 30), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act & Assert: the result should not depend on the order of the rectangles.
+			require.Equal(t, tt.want, binpack.Overlap(tt.a, tt.b))
+			require.Equal(t, tt.want, binpack.Overlap(tt.b, tt.a))
+		})
+	}
+}
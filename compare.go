@@ -0,0 +1,41 @@
+package binpack
+
+import "time"
+
+// CompareResult describes the layout produced by one heuristic in Compare.
+type CompareResult struct {
+	// Heuristic is the heuristic that produced the layout.
+	Heuristic Heuristic
+	// Width and Height are the overall dimensions of the layout.
+	Width, Height int
+	// Occupancy is the fraction of the layout covered by rectangles, from 0
+	// to 1. An empty layout has no occupancy.
+	Occupancy float64
+	// Unplaced holds the indices of the rectangles that could not be placed,
+	// in ascending order.
+	Unplaced []int
+	// Elapsed is how long the heuristic took.
+	Elapsed time.Duration
+}
+
+// Compare packs the rectangles in p with each of the heuristics and returns
+// the results in the same order, so the best heuristic for a set of
+// rectangles can be chosen by measurement. The rectangles are never placed,
+// so p is left untouched.
+func Compare(p Packable, heuristics []Heuristic) []CompareResult {
+	var results = make([]CompareResult, 0, len(heuristics))
+	for _, heuristic := range heuristics {
+		var start = time.Now()
+		var layout, unplaced, _ = pack(p, Options{Heuristic: heuristic})
+		var elapsed = time.Since(start)
+		results = append(results, CompareResult{
+			Heuristic: heuristic,
+			Width:     layout.Width,
+			Height:    layout.Height,
+			Occupancy: occupancy(layout),
+			Unplaced:  unplaced,
+			Elapsed:   elapsed,
+		})
+	}
+	return results
+}
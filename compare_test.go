@@ -0,0 +1,40 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCompare verifies that each heuristic is measured without placing the
+// rectangles.
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: four tiles that fill a square exactly.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+	})
+	for i := range tp.placements {
+		tp.placements[i].x = -1
+	}
+
+	// Act: compare the grid and default heuristics.
+	results := binpack.Compare(tp, []binpack.Heuristic{binpack.HeuristicGrid, binpack.HeuristicDefault})
+
+	// Assert: each result fills the square, and the Packable is untouched.
+	require.Len(t, results, 2)
+	require.Equal(t, binpack.HeuristicGrid, results[0].Heuristic)
+	require.Equal(t, 20, results[0].Width)
+	require.Equal(t, 20, results[0].Height)
+	require.InDelta(t, 1, results[0].Occupancy, 1e-9)
+	require.Equal(t, binpack.HeuristicDefault, results[1].Heuristic)
+	require.InDelta(t, 1, results[1].Occupancy, 1e-9)
+	for _, placement := range tp.placements {
+		require.Equal(t, -1, placement.x)
+	}
+}
@@ -0,0 +1,92 @@
+package binpack
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+)
+
+// ComposeMulti packs the images of several channels that share a layout, such
+// as the color, normal and emissive maps of a set of sprites, and draws each
+// channel onto its own canvas. channels[c][i] is image i of channel c. The
+// layout is computed once from the sizes of the first channel, and every
+// channel is drawn at the same positions, which are returned indexed by
+// image. Images of the other channels may be nil to leave their area
+// transparent, but must otherwise match the size of the image in the first
+// channel. The images are drawn as they are, so options that rotate, flip
+// or move the layout away from the canvas, namely AutoOrient, YUp, Relative
+// and KeepPinPositions, are not supported. Returns ErrInvalidInput if the
+// channels do not line up or such an option is set, or the error from
+// PackWithOptions if the images cannot be packed.
+func ComposeMulti(channels [][]image.Image, opts Options) ([]*image.RGBA, []image.Point, error) {
+	if len(channels) == 0 {
+		return nil, nil, fmt.Errorf("%w: no channels", ErrInvalidInput)
+	}
+	switch {
+	case opts.AutoOrient.Width > 0 && opts.AutoOrient.Height > 0:
+		return nil, nil, fmt.Errorf("%w: AutoOrient is not supported", ErrInvalidInput)
+	case opts.YUp:
+		return nil, nil, fmt.Errorf("%w: YUp is not supported", ErrInvalidInput)
+	case opts.Relative:
+		return nil, nil, fmt.Errorf("%w: Relative is not supported", ErrInvalidInput)
+	case opts.KeepPinPositions:
+		return nil, nil, fmt.Errorf("%w: KeepPinPositions is not supported", ErrInvalidInput)
+	}
+	var first = channels[0]
+	for i, img := range first {
+		if img == nil {
+			return nil, nil, fmt.Errorf("%w: image %d of the first channel is nil", ErrInvalidInput, i)
+		}
+	}
+	for c, channel := range channels[1:] {
+		if len(channel) != len(first) {
+			return nil, nil, fmt.Errorf("%w: channel %d has %d images, want %d", ErrInvalidInput, c+1, len(channel), len(first))
+		}
+		for i, img := range channel {
+			if img != nil && img.Bounds().Size() != first[i].Bounds().Size() {
+				return nil, nil, fmt.Errorf("%w: image %d of channel %d is %v, want %v", ErrInvalidInput, i, c+1, img.Bounds().Size(), first[i].Bounds().Size())
+			}
+		}
+	}
+
+	var images = imageSlice{images: first, positions: make([]image.Point, len(first))}
+	var layout, err = PackWithOptions(images, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var canvases = make([]*image.RGBA, len(channels))
+	for c, channel := range channels {
+		canvases[c] = image.NewRGBA(image.Rect(0, 0, layout.Width, layout.Height))
+		for i, img := range channel {
+			if img == nil {
+				continue
+			}
+			var dst = image.Rectangle{Min: images.positions[i], Max: images.positions[i].Add(img.Bounds().Size())}
+			draw.Draw(canvases[c], dst, img, img.Bounds().Min, draw.Src)
+		}
+	}
+	return canvases, images.positions, nil
+}
+
+// imageSlice is a Packable over images that records their positions.
+type imageSlice struct {
+	images    []image.Image
+	positions []image.Point
+}
+
+// Len returns the number of images.
+func (s imageSlice) Len() int {
+	return len(s.images)
+}
+
+// Rectangle returns the size of the image at index n.
+func (s imageSlice) Rectangle(n int) Rectangle {
+	var size = s.images[n].Bounds().Size()
+	return Rectangle{Width: size.X, Height: size.Y}
+}
+
+// Place records the position of the image at index n.
+func (s imageSlice) Place(n, x, y int) {
+	s.positions[n] = image.Pt(x, y)
+}
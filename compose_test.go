@@ -0,0 +1,105 @@
+package binpack_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// newUniformImage returns a w x h image filled with c.
+func newUniformImage(w, h int, c color.RGBA) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// TestComposeMulti verifies that every channel is drawn at the positions of
+// the shared layout.
+func TestComposeMulti(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: two sprites with a color and a normal channel.
+	red, green := color.RGBA{R: 255, A: 255}, color.RGBA{G: 255, A: 255}
+	normal := color.RGBA{R: 128, G: 128, B: 255, A: 255}
+	channels := [][]image.Image{
+		{newUniformImage(20, 10, red), newUniformImage(10, 10, green)},
+		{newUniformImage(20, 10, normal), nil},
+	}
+
+	// Act: compose the channels on shared canvases.
+	canvases, positions, err := binpack.ComposeMulti(channels, binpack.Options{})
+
+	// Assert: the normal map should only cover the first sprite.
+	require.NoError(t, err)
+	require.Len(t, canvases, 2)
+	require.Len(t, positions, 2)
+	require.Equal(t, canvases[0].Bounds(), canvases[1].Bounds())
+	require.Equal(t, red, canvases[0].RGBAAt(positions[0].X, positions[0].Y))
+	require.Equal(t, green, canvases[0].RGBAAt(positions[1].X, positions[1].Y))
+	require.Equal(t, normal, canvases[1].RGBAAt(positions[0].X+19, positions[0].Y+9))
+	require.Equal(t, color.RGBA{}, canvases[1].RGBAAt(positions[1].X, positions[1].Y))
+}
+
+// TestComposeMulti_Mismatched verifies that channels that do not line up are
+// rejected.
+func TestComposeMulti_Mismatched(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		channels [][]image.Image
+	}{
+		{name: "None"},
+		{name: "Count", channels: [][]image.Image{{newUniformImage(1, 1, color.RGBA{})}, {}}},
+		{name: "Size", channels: [][]image.Image{{newUniformImage(1, 1, color.RGBA{})}, {newUniformImage(2, 1, color.RGBA{})}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: compose the channels.
+			_, _, err := binpack.ComposeMulti(tt.channels, binpack.Options{})
+
+			// Assert: the channels are rejected as invalid input.
+			require.ErrorIs(t, err, binpack.ErrInvalidInput)
+		})
+	}
+}
+
+// TestComposeMulti_UnsupportedOptions verifies that options that would move
+// the layout away from the drawn images are rejected.
+func TestComposeMulti_UnsupportedOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+	}{
+		{name: "AutoOrient", opts: binpack.Options{AutoOrient: binpack.Rectangle{Width: 9, Height: 16}}},
+		{name: "YUp", opts: binpack.Options{YUp: true}},
+		{name: "Relative", opts: binpack.Options{Relative: true, RelativeTo: 1}},
+		{name: "KeepPinPositions", opts: binpack.Options{KeepPinPositions: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: a single channel of two sprites.
+			channels := [][]image.Image{{newUniformImage(20, 10, color.RGBA{R: 255, A: 255}), newUniformImage(10, 10, color.RGBA{G: 255, A: 255})}}
+
+			// Act: compose it with the option.
+			_, _, err := binpack.ComposeMulti(channels, tt.opts)
+
+			// Assert: the option is named in the invalid input error.
+			require.ErrorIs(t, err, binpack.ErrInvalidInput)
+			require.ErrorContains(t, err, tt.name)
+		})
+	}
+}
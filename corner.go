@@ -0,0 +1,75 @@
+package binpack
+
+import "math"
+
+// Rounder is an optional interface for Packables whose rectangles have
+// rounded corners, such as the cards of a user interface. The area outside
+// the arc of each corner is left free, so a neighbour may reach into it
+// diagonally as long as the rounded shapes do not overlap, while the
+// rectangles still never overlap along their sides. The packer tries tucking
+// each rectangle up and to the left into such a corner. Layout.Overlaps does
+// not list the corners crossed, and ValidateLayout allows them. Rounding is
+// ignored when Options.MaxOverlapRatio allows overlap.
+type Rounder interface {
+	// CornerRadius returns the radius of the corners of the rectangle at
+	// index n. It is clamped between zero and half the shorter side.
+	CornerRadius(n int) int
+}
+
+// cornerRadii returns a function that reports the clamped corner radius of
+// the rectangle at index n of p, or nil if p does not implement Rounder.
+func cornerRadii(p Packable) func(n int) int {
+	var r, ok = optional[Rounder](p)
+	if !ok {
+		return nil
+	}
+	return func(n int) int {
+		var rectangle = p.Rectangle(n)
+		return min(max(r.CornerRadius(n), 0), min(rectangle.Width, rectangle.Height)/2)
+	}
+}
+
+// roundedIntersect returns true if a and b share any area once their corners
+// are rounded with radii ra and rb. A rounded rectangle holds the points
+// within its radius of its core, the rectangle inset by the radius, so the
+// shapes overlap when their cores are closer than the sum of the radii.
+// Rectangles whose cores overlap on either axis meet along a side, where
+// rounding makes no difference.
+func roundedIntersect(a, b placement, ra, rb int) bool {
+	if !doRectanglesIntersect(a, b) {
+		return false
+	}
+	var dx = int64(max(b.x+rb-(a.x+a.width-ra), a.x+ra-(b.x+b.width-rb), 0))
+	var dy = int64(max(b.y+rb-(a.y+a.height-ra), a.y+ra-(b.y+b.height-rb), 0))
+	if dx == 0 || dy == 0 {
+		return true
+	}
+	var reach = int64(ra + rb)
+	return dx*dx+dy*dy < reach*reach
+}
+
+// clearOfCorners returns true if candidate, with the corner radius of c,
+// overlaps none of the placements once the corners are rounded.
+func (c constraints) clearOfCorners(candidate placement, placements []placement) bool {
+	for _, p := range placements {
+		if roundedIntersect(candidate, p, c.radius, c.radii(p.position)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cornerTuck returns how far a rectangle with the corner radius of c can be
+// tucked diagonally into the corner of the most rounded of the placements,
+// as the most the corners of two rounded rectangles can cross: the sum of
+// the radii less its projection onto the diagonal.
+func (c constraints) cornerTuck(placements []placement) int {
+	if c.radii == nil {
+		return 0
+	}
+	var largest int
+	for _, p := range placements {
+		largest = max(largest, c.radii(p.position))
+	}
+	return int(float64(c.radius+largest) * (1 - 1/math.Sqrt2))
+}
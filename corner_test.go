@@ -0,0 +1,88 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testRounderPackable extends testPackable with a corner radius shared by
+// every rectangle.
+type testRounderPackable struct {
+	*testPackable
+	radius int
+}
+
+// Ensure that testRounderPackable implements the binpack.Rounder interface.
+var _ binpack.Rounder = (*testRounderPackable)(nil)
+
+// CornerRadius returns the same radius for every rectangle.
+func (tp *testRounderPackable) CornerRadius(int) int {
+	return tp.radius
+}
+
+// TestPackWithOptions_Rounder verifies that a rounded rectangle is tucked
+// diagonally into the corner of another when they would not fit side by side.
+func TestPackWithOptions_Rounder(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: two discs that only fit the bin diagonally.
+	rectangles := []binpack.Rectangle{{Width: 60, Height: 60}, {Width: 60, Height: 60}}
+	opts := binpack.Options{MaxWidth: 105, MaxHeight: 105}
+	tp := &testRounderPackable{testPackable: newTestPackable(rectangles), radius: 30}
+
+	// Act: pack as squares and with the rounded corners.
+	_, square := binpack.PackWithOptions(newTestPackable(rectangles), opts)
+	layout, err := binpack.PackWithOptions(tp, opts)
+
+	// Assert: the corners are crossed as far as the arcs allow.
+	require.ErrorIs(t, square, binpack.ErrTooLarge)
+	require.NoError(t, err)
+	require.Equal(t, 0, tp.placements[0].x)
+	require.Equal(t, 0, tp.placements[0].y)
+	require.Equal(t, 43, tp.placements[1].x)
+	require.Equal(t, 43, tp.placements[1].y)
+	require.Equal(t, 103, layout.Width)
+	require.NoError(t, binpack.ValidateLayout(tp, layout))
+}
+
+// TestValidateLayout_Rounder verifies that rounded corners may only be
+// crossed where their arcs leave room.
+func TestValidateLayout_Rounder(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		offset int
+		valid  bool
+	}{
+		{name: "Clear", offset: 43, valid: true},
+		{name: "TooDeep", offset: 42, valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: two discs, the second offset diagonally from the first.
+			tp := &testRounderPackable{testPackable: newTestPackable([]binpack.Rectangle{{Width: 60, Height: 60}, {Width: 60, Height: 60}}), radius: 30}
+			layout := binpack.Layout{
+				Width: tt.offset + 60, Height: tt.offset + 60,
+				Placements: []binpack.Placement{
+					{Index: 0, X: 0, Y: 0, Width: 60, Height: 60},
+					{Index: 1, X: tt.offset, Y: tt.offset, Width: 60, Height: 60},
+				},
+			}
+
+			// Act: validate the layout.
+			err := binpack.ValidateLayout(tp, layout)
+
+			// Assert: the corners may only overlap where the arcs leave room.
+			if tt.valid {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, binpack.ErrInvalidLayout)
+			}
+		})
+	}
+}
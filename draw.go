@@ -0,0 +1,57 @@
+package binpack
+
+import (
+	"image"
+	"image/draw"
+)
+
+// DrawOp describes where to draw one rectangle of a layout.
+type DrawOp struct {
+	// Index is the index of the rectangle in the Packable.
+	Index int
+	// Dst is the area of the layout the rectangle covers.
+	Dst image.Rectangle
+}
+
+// DrawOps returns the area each rectangle of the layout covers, in the order
+// of its Placements, followed by the rectangles in its Refs, which cover the
+// same area as the rectangle they refer to. Rectangles with no area are left
+// out, since there is nothing to draw. Each source image can then be drawn
+// with draw.Draw(dst, op.Dst, src, src.Bounds().Min, draw.Over).
+func DrawOps(layout Layout) []DrawOp {
+	var ops = make([]DrawOp, 0, len(layout.Placements)+len(layout.Refs))
+	var areas = make(map[int]image.Rectangle, len(layout.Placements))
+	for _, p := range layout.Placements {
+		if p.Width <= 0 || p.Height <= 0 {
+			continue
+		}
+		var dst = image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height)
+		ops = append(ops, DrawOp{Index: p.Index, Dst: dst})
+		areas[p.Index] = dst
+	}
+	for _, ref := range layout.Refs {
+		if dst, ok := areas[ref.Ref]; ok {
+			ops = append(ops, DrawOp{Index: ref.Index, Dst: dst})
+		}
+	}
+	return ops
+}
+
+// Extract copies the area of each placement of the layout out of the atlas it
+// was drawn onto, such as to verify an atlas or to recover lost sources. The
+// images are returned in the order of Placements, each with its bounds at the
+// origin, and rectangles with no area get an empty image. The layout is
+// measured from the top-left corner of the atlas bounds, so layouts packed
+// with Options.YUp must be flipped back first. Layouts do not record
+// rotation, so a rectangle turned by Layout.Rotate90 is returned as it
+// appears in the atlas and must be turned back by the caller.
+func Extract(atlas image.Image, layout Layout) []image.Image {
+	var images = make([]image.Image, len(layout.Placements))
+	var origin = atlas.Bounds().Min
+	for i, p := range layout.Placements {
+		var img = image.NewRGBA(image.Rect(0, 0, max(p.Width, 0), max(p.Height, 0)))
+		draw.Draw(img, img.Bounds(), atlas, origin.Add(image.Pt(p.X, p.Y)), draw.Src)
+		images[i] = img
+	}
+	return images
+}
@@ -0,0 +1,77 @@
+package binpack_test
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrawOps verifies that each placement becomes the destination area of a
+// draw operation, and that repeated rectangles share the area they refer to.
+func TestDrawOps(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: a layout with an empty rectangle and a repeated one.
+	layout := binpack.Layout{
+		Width:  30,
+		Height: 10,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 10, Y: 0, Width: 20, Height: 5},
+			{Index: 2, X: 0, Y: 0, Width: 0, Height: 0},
+		},
+		Refs: []binpack.PlacementRef{{Index: 3, Ref: 1}},
+	}
+
+	// Act: list the draw operations.
+	ops := binpack.DrawOps(layout)
+
+	// Assert: the empty rectangle should be left out.
+	require.Equal(t, []binpack.DrawOp{
+		{Index: 0, Dst: image.Rect(0, 0, 10, 10)},
+		{Index: 1, Dst: image.Rect(10, 0, 30, 5)},
+		{Index: 3, Dst: image.Rect(10, 0, 30, 5)},
+	}, ops)
+}
+
+// TestExtract verifies that the area of each placement is copied out of the
+// atlas with its bounds at the origin.
+func TestExtract(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: fill each placement of an atlas with its own color.
+	layout := binpack.Layout{
+		Width:  30,
+		Height: 10,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 10, Y: 0, Width: 20, Height: 5},
+			{Index: 2, X: 0, Y: 0, Width: 0, Height: 0},
+		},
+	}
+	colors := []color.RGBA{{R: 255, A: 255}, {G: 255, A: 255}}
+	atlas := image.NewRGBA(image.Rect(5, 5, 35, 15))
+	for i, p := range layout.Placements[:2] {
+		draw.Draw(atlas, image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height).Add(atlas.Bounds().Min), image.NewUniform(colors[i]), image.Point{}, draw.Src)
+	}
+
+	// Act: extract the images from the atlas.
+	images := binpack.Extract(atlas, layout)
+
+	// Assert: each image holds only its own color, and the empty one is empty.
+	require.Len(t, images, 3)
+	require.Equal(t, image.Rect(0, 0, 10, 10), images[0].Bounds())
+	require.Equal(t, image.Rect(0, 0, 20, 5), images[1].Bounds())
+	require.True(t, images[2].Bounds().Empty())
+	for i, img := range images[:2] {
+		for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+			for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+				require.Equal(t, colors[i], img.At(x, y), "pixel (%d, %d) of image %d", x, y, i)
+			}
+		}
+	}
+}
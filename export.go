@@ -0,0 +1,102 @@
+package binpack
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ErrInvalidName is returned when a rectangle in a layout has no name, or
+// shares its name with another rectangle.
+var ErrInvalidName = errors.New("binpack: invalid rectangle name")
+
+// MarshalGodotAtlas encodes the layout as a Godot 4 text resource holding one
+// AtlasTexture sub-resource per placement. names[i] is the name of the
+// rectangle at index i and becomes the id of its sub-resource; the resource's
+// regions metadata maps each name to its sub-resource. The rectangles in the
+// Refs of the layout follow, with the region of the placement they share.
+// Assign the atlas texture to the sub-resources after importing.
+func MarshalGodotAtlas(layout Layout, names []string) ([]byte, error) {
+	type region struct {
+		index int
+		Placement
+	}
+	var regions = make([]region, 0, len(layout.Placements)+len(layout.Refs))
+	var placements = make(map[int]Placement, len(layout.Placements))
+	for _, p := range layout.Placements {
+		regions = append(regions, region{index: p.Index, Placement: p})
+		placements[p.Index] = p
+	}
+	for _, ref := range layout.Refs {
+		if p, ok := placements[ref.Ref]; ok {
+			regions = append(regions, region{index: ref.Index, Placement: p})
+		}
+	}
+
+	var seen = make(map[string]bool, len(regions))
+	for _, r := range regions {
+		if r.index < 0 || r.index >= len(names) || names[r.index] == "" {
+			return nil, fmt.Errorf("%w: rectangle %d has no name", ErrInvalidName, r.index)
+		}
+		if seen[names[r.index]] {
+			return nil, fmt.Errorf("%w: %q is used more than once", ErrInvalidName, names[r.index])
+		}
+		seen[names[r.index]] = true
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "[gd_resource type=\"Resource\" load_steps=%d format=3]\n", len(regions)+1)
+	for _, r := range regions {
+		fmt.Fprintf(&b, "\n[sub_resource type=\"AtlasTexture\" id=%s]\n", strconv.Quote(names[r.index]))
+		fmt.Fprintf(&b, "region = Rect2(%d, %d, %d, %d)\n", r.X, r.Y, r.Width, r.Height)
+	}
+
+	// Map each name to its region so the sprites can be looked up by name.
+	b.WriteString("\n[resource]\nmetadata/regions = {\n")
+	for _, r := range regions {
+		var name = strconv.Quote(names[r.index])
+		fmt.Fprintf(&b, "%s: SubResource(%s),\n", name, name)
+	}
+	b.WriteString("}\n")
+
+	return b.Bytes(), nil
+}
+
+// WriteCSV writes the layout to w as CSV, with a header row followed by one
+// "name,index,x,y,width,height" row per placement. names[i] is the name of
+// the rectangle at index i; rectangles without a name have an empty name.
+// The rectangles in the Refs of the layout follow, with the position and size
+// of the placement they share.
+func WriteCSV(w io.Writer, layout Layout, names []string) error {
+	var cw = csv.NewWriter(w)
+	var row = func(index int, p Placement) []string {
+		var name string
+		if index >= 0 && index < len(names) {
+			name = names[index]
+		}
+		return []string{
+			name,
+			strconv.Itoa(index),
+			strconv.Itoa(p.X),
+			strconv.Itoa(p.Y),
+			strconv.Itoa(p.Width),
+			strconv.Itoa(p.Height),
+		}
+	}
+
+	var records = [][]string{{"name", "index", "x", "y", "width", "height"}}
+	var placements = make(map[int]Placement, len(layout.Placements))
+	for _, p := range layout.Placements {
+		records = append(records, row(p.Index, p))
+		placements[p.Index] = p
+	}
+	for _, ref := range layout.Refs {
+		if p, ok := placements[ref.Ref]; ok {
+			records = append(records, row(ref.Index, p))
+		}
+	}
+	return cw.WriteAll(records)
+}
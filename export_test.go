@@ -0,0 +1,141 @@
+package binpack_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshalGodotAtlas verifies that each placement is encoded as a named region.
+func TestMarshalGodotAtlas(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with two sprites.
+	layout := binpack.Layout{
+		Width:  48,
+		Height: 32,
+		Placements: []binpack.Placement{
+			{Index: 1, X: 0, Y: 0, Width: 32, Height: 32},
+			{Index: 0, X: 32, Y: 0, Width: 16, Height: 16},
+		},
+	}
+
+	// Act: marshal the layout.
+	b, err := binpack.MarshalGodotAtlas(layout, []string{"coin", "hero"})
+
+	// Assert: the resource should list each region by name.
+	require.NoError(t, err)
+	require.Equal(t, `[gd_resource type="Resource" load_steps=3 format=3]
+
+[sub_resource type="AtlasTexture" id="hero"]
+region = Rect2(0, 0, 32, 32)
+
+[sub_resource type="AtlasTexture" id="coin"]
+region = Rect2(32, 0, 16, 16)
+
+[resource]
+metadata/regions = {
+"hero": SubResource("hero"),
+"coin": SubResource("coin"),
+}
+`, string(b))
+}
+
+// TestMarshalGodotAtlas_Refs verifies that repeated rectangles are encoded
+// with the region of the placement they share.
+func TestMarshalGodotAtlas_Refs(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with a sprite repeated once.
+	layout := binpack.Layout{
+		Width:      16,
+		Height:     16,
+		Placements: []binpack.Placement{{Index: 0, X: 0, Y: 0, Width: 16, Height: 16}},
+		Refs:       []binpack.PlacementRef{{Index: 1, Ref: 0}},
+	}
+
+	// Act: marshal the layout.
+	b, err := binpack.MarshalGodotAtlas(layout, []string{"coin", "coin_copy"})
+
+	// Assert: the repeat should have a region of its own at the same position.
+	require.NoError(t, err)
+	require.Equal(t, `[gd_resource type="Resource" load_steps=3 format=3]
+
+[sub_resource type="AtlasTexture" id="coin"]
+region = Rect2(0, 0, 16, 16)
+
+[sub_resource type="AtlasTexture" id="coin_copy"]
+region = Rect2(0, 0, 16, 16)
+
+[resource]
+metadata/regions = {
+"coin": SubResource("coin"),
+"coin_copy": SubResource("coin_copy"),
+}
+`, string(b))
+}
+
+// TestMarshalGodotAtlas_InvalidNames verifies that missing and duplicate names
+// are rejected.
+func TestMarshalGodotAtlas_InvalidNames(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with two sprites.
+	layout := binpack.Layout{
+		Placements: []binpack.Placement{
+			{Index: 0, Width: 16, Height: 16},
+			{Index: 1, X: 16, Width: 16, Height: 16},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		names []string
+	}{
+		{name: "Missing", names: []string{"coin"}},
+		{name: "Empty", names: []string{"coin", ""}},
+		{name: "Duplicate", names: []string{"coin", "coin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: marshal the layout.
+			_, err := binpack.MarshalGodotAtlas(layout, tt.names)
+
+			// Assert: the error should be ErrInvalidName.
+			require.ErrorIs(t, err, binpack.ErrInvalidName)
+		})
+	}
+}
+
+// TestWriteCSV verifies that each placement is written as a row after the
+// header, including the repeats that share a placement.
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with two sprites, one of which is repeated.
+	layout := binpack.Layout{
+		Width:  48,
+		Height: 32,
+		Placements: []binpack.Placement{
+			{Index: 1, X: 0, Y: 0, Width: 32, Height: 32},
+			{Index: 0, X: 32, Y: 0, Width: 16, Height: 16},
+		},
+		Refs: []binpack.PlacementRef{{Index: 2, Ref: 0}},
+	}
+	var b bytes.Buffer
+
+	// Act: write the layout.
+	err := binpack.WriteCSV(&b, layout, []string{"coin", "hero, big"})
+
+	// Assert: names should be quoted where needed, and missing names empty.
+	require.NoError(t, err)
+	require.Equal(t, `name,index,x,y,width,height
+"hero, big",1,0,0,32,32
+coin,0,32,0,16,16
+,2,32,0,16,16
+`, b.String())
+}
@@ -0,0 +1,89 @@
+package binpack
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnevenHeight is returned by PackWithOptions with HeuristicFilmstrip when
+// the rectangles are not all the same height.
+var ErrUnevenHeight = errors.New("binpack: rectangles differ in height")
+
+// uniformHeight returns ErrUnevenHeight if the rectangles in p that are not
+// skipped differ in height, naming the first that differs from the others.
+func uniformHeight(p Packable) error {
+	var skip = skipper(p)
+	var first = -1
+	for i := 0; i < p.Len(); i++ {
+		if skip(i) {
+			continue
+		}
+		if first < 0 {
+			first = i
+			continue
+		}
+		if height, want := p.Rectangle(i).Height, p.Rectangle(first).Height; height != want {
+			return fmt.Errorf("%w: rectangle %d is %d high, want %d", ErrUnevenHeight, i, height, want)
+		}
+	}
+	return nil
+}
+
+// packFilmstrip places the rectangles in p in index order from left to
+// right, starting a new row below when the next would exceed MaxWidth, so
+// the frames of an animation keep their order. Every row is as tall as the
+// first rectangle that is not skipped. Rectangles of another height, and
+// those that do not fit within the limits in opts, are left unplaced.
+func packFilmstrip(p Packable, opts Options) (Layout, []int, []int) {
+	var skip = skipper(p)
+	var layout Layout
+	var unplaced []int
+	var height = -1
+	var x, y int
+	for i := 0; i < p.Len(); i++ {
+		if skip(i) {
+			continue
+		}
+		opts.Stats.countPlacement()
+		var rectangle = p.Rectangle(i)
+		if height < 0 {
+			height = rectangle.Height
+		}
+		if rectangle.Height != height {
+			unplaced = append(unplaced, i)
+			continue
+		}
+
+		// Wrap to the next row if the frame would overhang the current one.
+		if x > 0 && !opts.fits(bounds{maxX: x + rectangle.Width, maxY: y + height}) {
+			x, y = 0, y+height
+		}
+		if !opts.fits(bounds{maxX: x + rectangle.Width, maxY: y + height}) {
+			unplaced = append(unplaced, i)
+			continue
+		}
+
+		layout.Placements = append(layout.Placements, Placement{
+			Index:  i,
+			X:      x + opts.margin(),
+			Y:      y + opts.margin(),
+			Width:  rectangle.Width,
+			Height: rectangle.Height,
+		})
+		layout.Width = max(layout.Width, x+rectangle.Width)
+		layout.Height = max(layout.Height, y+height)
+		x += rectangle.Width
+	}
+
+	if len(layout.Placements) > 0 {
+		layout.Width += 2 * opts.margin()
+		layout.Height += 2 * opts.margin()
+	}
+	layout = layout.quantize(p, opts, 0, 0)
+	layout.Width, layout.Height = opts.extent(layout.Width), opts.extent(layout.Height)
+	layout = opts.orient(layout)
+	if opts.YUp {
+		layout.flipY()
+	}
+	return layout, unplaced, nil
+}
@@ -0,0 +1,62 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackWithOptions_Filmstrip verifies that frames are placed in index
+// order, wrapping to a new row at the maximum width.
+func TestPackWithOptions_Filmstrip(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create frames of the same height and varying widths.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 20},
+		{Width: 30, Height: 20},
+		{Width: 20, Height: 20},
+		{Width: 40, Height: 20},
+		{Width: 5, Height: 20},
+	})
+
+	// Act: pack them as a filmstrip 60 pixels wide.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Heuristic: binpack.HeuristicFilmstrip, MaxWidth: 60})
+
+	// Assert: the frames should fill each row in order before wrapping.
+	require.NoError(t, err)
+	require.Equal(t, 60, layout.Width)
+	require.Equal(t, 40, layout.Height)
+	expected := []struct{ x, y int }{{0, 0}, {10, 0}, {40, 0}, {0, 20}, {40, 20}}
+	for i, e := range expected {
+		require.Equal(t, i, layout.Placements[i].Index)
+		require.Equal(t, e.x, tp.placements[i].x, "expected x-coordinate %d for frame %d", e.x, i)
+		require.Equal(t, e.y, tp.placements[i].y, "expected y-coordinate %d for frame %d", e.y, i)
+	}
+}
+
+// TestPackWithOptions_FilmstripUnevenHeight verifies that frames of different
+// heights are rejected, or left unplaced on a best effort.
+func TestPackWithOptions_FilmstripUnevenHeight(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: three frames, the second taller than the others.
+	rectangles := []binpack.Rectangle{
+		{Width: 10, Height: 20},
+		{Width: 10, Height: 30},
+		{Width: 10, Height: 20},
+	}
+	opts := binpack.Options{Heuristic: binpack.HeuristicFilmstrip}
+
+	// Act: pack them strictly and on a best effort.
+	_, err := binpack.PackWithOptions(newTestPackable(rectangles), opts)
+	layout, unplaced := binpack.PackBestEffort(newTestPackable(rectangles), opts)
+
+	// Assert: the taller frame is rejected, or left unplaced.
+	require.ErrorIs(t, err, binpack.ErrUnevenHeight)
+	require.ErrorContains(t, err, "rectangle 1 is 30 high, want 20")
+	require.Equal(t, []int{1}, unplaced)
+	require.Equal(t, 20, layout.Width)
+	require.Equal(t, 20, layout.Height)
+}
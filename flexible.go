@@ -0,0 +1,55 @@
+package binpack
+
+// Flexible is an optional interface for Packables whose rectangles can
+// shrink to fit, such as the panels of a responsive layout. Each rectangle is
+// first packed at the size returned by Rectangle. If it does not fit within
+// the limits, it is shrunk from its preferred size toward its minimum size,
+// keeping the proportions of the preferred size, and placed at the largest
+// size that fits. Resize is called with the chosen size before Place for
+// every rectangle placed at a size other than the one returned by Rectangle.
+// Rectangles in exclusive groups are never shrunk.
+type Flexible interface {
+	// SizeRange returns the smallest and the preferred size of the rectangle
+	// at index n.
+	SizeRange(n int) (min, preferred Rectangle)
+	// Resize sets the size of the rectangle at index n.
+	Resize(n, w, h int)
+}
+
+// shrinkToFit locates the largest size of the rectangle at index n between
+// its preferred and minimum sizes that fits among the existing placements,
+// assuming that a size fits if any larger one does. Returns the size and its
+// position, or false if even the minimum size does not fit.
+func shrinkToFit(f Flexible, n int, placements []placement, candidates *candidateSet, b bounds, c constraints, opts Options) (Rectangle, int, int, bool) {
+	var smallest, preferred = f.SizeRange(n)
+	if preferred.Width <= 0 || preferred.Height <= 0 {
+		return Rectangle{}, 0, 0, false
+	}
+
+	// Scale the preferred size by width, starting from the narrowest width
+	// whose height reaches the minimum.
+	var sized = func(width int) Rectangle {
+		var height = int((int64(width)*int64(preferred.Height) + int64(preferred.Width)/2) / int64(preferred.Width))
+		return Rectangle{Width: width, Height: min(max(height, smallest.Height), preferred.Height)}
+	}
+	var lo = max(smallest.Width, int((int64(smallest.Height)*int64(preferred.Width)+int64(preferred.Height)-1)/int64(preferred.Height)), 1)
+	if lo > preferred.Width {
+		return Rectangle{}, 0, 0, false
+	}
+	var x, y, ok = locate(sized(lo), placements, candidates, b, c, opts)
+	if !ok {
+		return Rectangle{}, 0, 0, false
+	}
+
+	// Search for the widest size that still fits.
+	var best, hi = lo, preferred.Width
+	for best < hi {
+		var width = best + (hi-best+1)/2
+		if wx, wy, fits := locate(sized(width), placements, candidates, b, c, opts); fits {
+			best, x, y = width, wx, wy
+		} else {
+			hi = width - 1
+		}
+	}
+	return sized(best), x, y, true
+}
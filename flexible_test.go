@@ -0,0 +1,67 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testFlexiblePackable extends testPackable with rectangles that can shrink to
+// half their size.
+type testFlexiblePackable struct {
+	*testPackable
+	sizes map[int]binpack.Rectangle
+}
+
+// Ensure that testFlexiblePackable implements the binpack.Flexible interface.
+var _ binpack.Flexible = (*testFlexiblePackable)(nil)
+
+// SizeRange returns half the size of the rectangle as its minimum.
+func (tp *testFlexiblePackable) SizeRange(n int) (binpack.Rectangle, binpack.Rectangle) {
+	r := tp.rectangles[n]
+	return binpack.Rectangle{Width: r.Width / 2, Height: r.Height / 2}, r
+}
+
+// Resize records the size chosen for the rectangle at the specified index.
+func (tp *testFlexiblePackable) Resize(n, w, h int) {
+	tp.sizes[n] = binpack.Rectangle{Width: w, Height: h}
+}
+
+// TestFlexible_Pack verifies that a rectangle that does not fit at its
+// preferred size is shrunk, keeping its proportions, to the largest size that
+// fits.
+func TestFlexible_Pack(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		second   binpack.Rectangle
+		want     map[int]binpack.Rectangle
+		unplaced []int
+	}{
+		{name: "Shrunk", second: binpack.Rectangle{Width: 100, Height: 60}, want: map[int]binpack.Rectangle{1: {Width: 67, Height: 40}}},
+		{name: "Preferred", second: binpack.Rectangle{Width: 100, Height: 40}, want: map[int]binpack.Rectangle{}},
+		{name: "TooSmall", second: binpack.Rectangle{Width: 60, Height: 90}, want: map[int]binpack.Rectangle{}, unplaced: []int{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: leave a 100x40 strip below the first rectangle.
+			tp := &testFlexiblePackable{
+				testPackable: newTestPackable([]binpack.Rectangle{{Width: 100, Height: 60}, tt.second}),
+				sizes:        make(map[int]binpack.Rectangle),
+			}
+
+			// Act: pack as many as fit within 100x100.
+			layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{MaxWidth: 100, MaxHeight: 100})
+
+			// Assert: the second rectangle takes the size that fits the strip, if any.
+			require.Equal(t, tt.unplaced, unplaced)
+			require.Equal(t, tt.want, tp.sizes)
+			require.LessOrEqual(t, layout.Height, 100)
+			requireNoOverlap(t, layout)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package binpack
+
+import (
+	"slices"
+	"sort"
+)
+
+// largestFree returns the area of the largest rectangle within b that no
+// placement covers. The edges of the placements divide b into a grid of
+// cells, and the largest run of free cells is found row by row as the largest
+// rectangle under a histogram of the free height above each column.
+func largestFree(b bounds, placements []placement) int64 {
+	var xs, ys = []int{b.minX, b.maxX}, []int{b.minY, b.maxY}
+	for _, p := range placements {
+		xs = append(xs, min(max(p.x, b.minX), b.maxX), min(max(p.x+p.width, b.minX), b.maxX))
+		ys = append(ys, min(max(p.y, b.minY), b.maxY), min(max(p.y+p.height, b.minY), b.maxY))
+	}
+	slices.Sort(xs)
+	slices.Sort(ys)
+	xs, ys = slices.Compact(xs), slices.Compact(ys)
+	var columns, rows = len(xs) - 1, len(ys) - 1
+	if columns <= 0 || rows <= 0 {
+		return 0
+	}
+
+	// Mark the cells covered by a placement.
+	var covered = make([]bool, columns*rows)
+	for _, p := range placements {
+		var left, right = sort.SearchInts(xs, p.x), sort.SearchInts(xs, p.x+p.width)
+		var top, bottom = sort.SearchInts(ys, p.y), sort.SearchInts(ys, p.y+p.height)
+		for row := max(top, 0); row < min(bottom, rows); row++ {
+			for column := max(left, 0); column < min(right, columns); column++ {
+				covered[row*columns+column] = true
+			}
+		}
+	}
+
+	type bar struct {
+		start  int
+		height int64
+	}
+	var largest int64
+	var heights = make([]int64, columns)
+	var stack = make([]bar, 0, columns+1)
+	for row := 0; row < rows; row++ {
+		for column := range heights {
+			if covered[row*columns+column] {
+				heights[column] = 0
+			} else {
+				heights[column] += int64(ys[row+1] - ys[row])
+			}
+		}
+
+		// Each bar extends right until a lower one ends it.
+		stack = stack[:0]
+		for column := 0; column <= columns; column++ {
+			var height int64
+			if column < columns {
+				height = heights[column]
+			}
+			var start = xs[column]
+			for len(stack) > 0 && stack[len(stack)-1].height >= height {
+				var top = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				largest = max(largest, top.height*int64(xs[column]-top.start))
+				start = top.start
+			}
+			stack = append(stack, bar{start: start, height: height})
+		}
+	}
+	return largest
+}
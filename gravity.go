@@ -0,0 +1,116 @@
+package binpack
+
+// Gravity selects the corner that rectangles are pushed toward after
+// packing.
+type Gravity int
+
+const (
+	// GravityNone leaves the rectangles where the packer placed them. This
+	// is the default.
+	GravityNone Gravity = iota
+	// GravityTopLeft pushes the rectangles up and to the left.
+	GravityTopLeft
+	// GravityTopRight pushes the rectangles up and to the right.
+	GravityTopRight
+	// GravityBottomLeft pushes the rectangles down and to the left.
+	GravityBottomLeft
+	// GravityBottomRight pushes the rectangles down and to the right.
+	GravityBottomRight
+)
+
+// settle pushes each placement after the first fixed ones, in order, as far
+// toward the corner chosen by g as it can go without leaving b or overlapping
+// another placement, and repeats until nothing moves. If allowed is not nil,
+// each placement only stops at positions it allows.
+func settle(placements []placement, fixed int, g Gravity, b bounds, allowed func(n, x, y int) bool) {
+	var flipX = g == GravityTopRight || g == GravityBottomRight
+	var flipY = g == GravityBottomLeft || g == GravityBottomRight
+
+	// Mirror the placements so the corner is always the top-left.
+	var mirror = func() {
+		for i := range placements {
+			if flipX {
+				placements[i].x = -(placements[i].x + placements[i].width)
+			}
+			if flipY {
+				placements[i].y = -(placements[i].y + placements[i].height)
+			}
+		}
+	}
+	if flipX {
+		b.minX = -b.maxX
+	}
+	if flipY {
+		b.minY = -b.maxY
+	}
+
+	// Check the mirrored positions against allowed in the original coordinates.
+	var permits func(c placement) bool
+	if allowed != nil {
+		permits = func(c placement) bool {
+			var x, y = c.x, c.y
+			if flipX {
+				x = -(x + c.width)
+			}
+			if flipY {
+				y = -(y + c.height)
+			}
+			return allowed(c.position, x, y)
+		}
+	}
+
+	mirror()
+	for moved := true; moved; {
+		moved = false
+		for i := fixed; i < len(placements); i++ {
+			for {
+				var up, left = slideUp(placements, i, b.minY, permits), slideLeft(placements, i, b.minX, permits)
+				if up == 0 && left == 0 {
+					break
+				}
+				moved = true
+			}
+		}
+	}
+	mirror()
+}
+
+// slideUp moves the placement at index i up until it meets another placement
+// or minY, and returns the distance moved. A placement that already overlaps
+// one above it stays put, so overlaps allowed by Options.MaxOverlapRatio do
+// not grow. If permits is not nil, the placement stops short at the furthest
+// position it permits.
+func slideUp(placements []placement, i int, minY int, permits func(c placement) bool) int {
+	var c = placements[i]
+	var distance = c.y - minY
+	for j, o := range placements {
+		if j != i && o.x < c.x+c.width && c.x < o.x+o.width && o.y < c.y {
+			distance = min(distance, c.y-(o.y+o.height))
+		}
+	}
+	distance = max(distance, 0)
+	for permits != nil && distance > 0 && !permits(placement{position: c.position, x: c.x, y: c.y - distance, width: c.width, height: c.height}) {
+		distance--
+	}
+	placements[i].y -= distance
+	return distance
+}
+
+// slideLeft moves the placement at index i left until it meets another
+// placement or minX, and returns the distance moved. If permits is not nil,
+// the placement stops short at the furthest position it permits.
+func slideLeft(placements []placement, i int, minX int, permits func(c placement) bool) int {
+	var c = placements[i]
+	var distance = c.x - minX
+	for j, o := range placements {
+		if j != i && o.y < c.y+c.height && c.y < o.y+o.height && o.x < c.x {
+			distance = min(distance, c.x-(o.x+o.width))
+		}
+	}
+	distance = max(distance, 0)
+	for permits != nil && distance > 0 && !permits(placement{position: c.position, x: c.x - distance, y: c.y, width: c.width, height: c.height}) {
+		distance--
+	}
+	placements[i].x -= distance
+	return distance
+}
@@ -0,0 +1,132 @@
+package binpack_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackWithOptions_Gravity verifies that pushing the rectangles toward a
+// corner closes the gaps left by the packer and shrinks the layout.
+func TestPackWithOptions_Gravity(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: the packer lines these up in a 14x2 strip, leaving a gap below the two wide rectangles.
+	rectangles := []binpack.Rectangle{{Width: 8, Height: 1}, {Width: 5, Height: 1}, {Width: 1, Height: 2}}
+	before, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+	require.Equal(t, 14, before.Width)
+	require.Equal(t, 2, before.Height)
+
+	// Act: pack them again with gravity toward the bottom-right corner.
+	after, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Gravity: binpack.GravityBottomRight})
+
+	// Assert: the widest rectangle settles into the gap.
+	require.NoError(t, err)
+	require.Equal(t, 9, after.Width)
+	require.Equal(t, 2, after.Height)
+	require.Equal(t, []binpack.Placement{
+		{Index: 0, X: 0, Y: 1, Width: 8, Height: 1},
+		{Index: 1, X: 3, Y: 0, Width: 5, Height: 1},
+		{Index: 2, X: 8, Y: 0, Width: 1, Height: 2},
+	}, after.Placements)
+	requireNoOverlap(t, after)
+}
+
+// TestPackWithOptions_GravityNeverGrows verifies that every gravity keeps the
+// rectangles apart and never makes the layout larger.
+func TestPackWithOptions_GravityNeverGrows(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		gravity binpack.Gravity
+	}{
+		{name: "top left", gravity: binpack.GravityTopLeft},
+		{name: "top right", gravity: binpack.GravityTopRight},
+		{name: "bottom left", gravity: binpack.GravityBottomLeft},
+		{name: "bottom right", gravity: binpack.GravityBottomRight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: forty random rectangles and their layout without gravity.
+			rectangles := randomRectangles(40)
+			before, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{MaxWidth: 600})
+			require.NoError(t, err)
+
+			// Act: pack them again with the gravity.
+			after, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{MaxWidth: 600, Gravity: tt.gravity})
+
+			// Assert: the layout is no larger and the rectangles do not overlap.
+			require.NoError(t, err)
+			require.LessOrEqual(t, after.Width, before.Width)
+			require.LessOrEqual(t, after.Height, before.Height)
+			require.Len(t, after.Placements, len(rectangles))
+			requireNoOverlap(t, after)
+		})
+	}
+}
+
+// TestPackWithOptions_GravityKeepsPins verifies that gravity moves the
+// rectangles around the pinned ones without moving them.
+func TestPackWithOptions_GravityKeepsPins(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pin two small rectangles at opposite corners.
+	tp := &testPinPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 4, Height: 4},
+			{Width: 10, Height: 10},
+			{Width: 4, Height: 4},
+		}),
+		pins: map[int]image.Point{0: {X: 0, Y: 0}, 2: {X: 20, Y: 20}},
+	}
+
+	// Act: pack with gravity toward the top-left corner.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Gravity: binpack.GravityTopLeft})
+
+	// Assert: the free rectangle settles against the top-left pin.
+	require.NoError(t, err)
+	require.Equal(t, binpack.Placement{Index: 0, X: 0, Y: 0, Width: 4, Height: 4}, layout.Placements[0])
+	require.Equal(t, binpack.Placement{Index: 2, X: 20, Y: 20, Width: 4, Height: 4}, layout.Placements[2])
+	require.Equal(t, 0, layout.Placements[1].Y)
+	require.Equal(t, 4, layout.Placements[1].X)
+	requireNoOverlap(t, layout)
+}
+
+// testTopRowPackable extends testPackable with the first rectangle held to
+// the top row.
+type testTopRowPackable struct {
+	*testPackable
+}
+
+// Ensure that testTopRowPackable implements the binpack.Allower interface.
+var _ binpack.Allower = (*testTopRowPackable)(nil)
+
+// Allowed returns false if the first rectangle would leave the top row.
+func (tp *testTopRowPackable) Allowed(n, x, y int) bool {
+	return n != 0 || y == 0
+}
+
+// TestPackWithOptions_GravityAllower verifies that gravity never moves a
+// rectangle to a position the Packable does not allow.
+func TestPackWithOptions_GravityAllower(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: without the veto, the widest rectangle settles into the gap below.
+	tp := &testTopRowPackable{testPackable: newTestPackable([]binpack.Rectangle{{Width: 8, Height: 1}, {Width: 5, Height: 1}, {Width: 1, Height: 2}})}
+
+	// Act: pack with gravity toward the bottom-right corner.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Gravity: binpack.GravityBottomRight})
+
+	// Assert: the widest rectangle stays on the top row.
+	require.NoError(t, err)
+	for _, p := range layout.Placements {
+		require.True(t, tp.Allowed(p.Index, p.X, p.Y), "rectangle %d moved to (%d, %d)", p.Index, p.X, p.Y)
+	}
+	requireNoOverlap(t, layout)
+}
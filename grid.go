@@ -0,0 +1,99 @@
+package binpack
+
+import (
+	"image"
+	"math"
+)
+
+// packGrid places the rectangles in p into the cells of a uniform grid in
+// row-major order. Skipped rectangles leave their cell empty. Rectangles that
+// are larger than a cell are scaled down to fit it, keeping their
+// proportions, and those whose cell lies outside the limits in opts are left
+// unplaced. Returns the layout and the indices of the rectangles that could
+// not be placed.
+func packGrid(p Packable, opts Options) (Layout, []int, []int) {
+	var count = p.Len()
+	var skip = skipper(p)
+	var columns = opts.Columns
+	if columns <= 0 {
+		columns = int(math.Ceil(math.Sqrt(float64(count))))
+	}
+
+	// Size the cells to the largest rectangles unless the options set them.
+	var cellWidth, cellHeight = opts.CellWidth, opts.CellHeight
+	if cellWidth <= 0 || cellHeight <= 0 {
+		var widest, tallest int
+		for i := 0; i < count; i++ {
+			if skip(i) {
+				continue
+			}
+			var rectangle = p.Rectangle(i)
+			widest = max(widest, rectangle.Width)
+			tallest = max(tallest, rectangle.Height)
+		}
+		if cellWidth <= 0 {
+			cellWidth = widest
+		}
+		if cellHeight <= 0 {
+			cellHeight = tallest
+		}
+	}
+
+	var layout Layout
+	var unplaced []int
+	for i := 0; i < count; i++ {
+		if skip(i) {
+			continue
+		}
+		opts.Stats.countPlacement()
+		var rectangle = scaleToCell(p.Rectangle(i), cellWidth, cellHeight)
+		var x, y = (i % columns) * cellWidth, (i / columns) * cellHeight
+		var cell = bounds{minX: 0, minY: 0, maxX: x + cellWidth, maxY: y + cellHeight}
+		if !opts.fits(cell) {
+			unplaced = append(unplaced, i)
+			continue
+		}
+
+		// Grow the layout to include the whole cell, even if the rectangle is smaller.
+		layout.Width = max(layout.Width, cell.maxX)
+		layout.Height = max(layout.Height, cell.maxY)
+
+		// Keep the cells clear of the edges.
+		x, y = x+opts.margin(), y+opts.margin()
+		layout.Cells = append(layout.Cells, image.Rect(x, y, x+cellWidth, y+cellHeight))
+		if opts.CenterInCell {
+			x += (cellWidth - rectangle.Width) / 2
+			y += (cellHeight - rectangle.Height) / 2
+		}
+		layout.Placements = append(layout.Placements, Placement{
+			Index:  i,
+			X:      x,
+			Y:      y,
+			Width:  rectangle.Width,
+			Height: rectangle.Height,
+		})
+	}
+
+	if len(layout.Placements) > 0 {
+		layout.Width += 2 * opts.margin()
+		layout.Height += 2 * opts.margin()
+	}
+	layout = layout.quantize(p, opts, 0, 0)
+	layout.Width, layout.Height = opts.extent(layout.Width), opts.extent(layout.Height)
+	layout = opts.orient(layout)
+	if opts.YUp {
+		layout.flipY()
+	}
+	return layout, unplaced, nil
+}
+
+// scaleToCell returns r scaled down by Rectangle.Scale to fit a cell of the
+// given dimensions, keeping its proportions, or r unchanged if it already
+// fits.
+func scaleToCell(r Rectangle, cellWidth, cellHeight int) Rectangle {
+	if r.Width <= cellWidth && r.Height <= cellHeight {
+		return r
+	}
+	var scaled = r.Scale(min(float64(cellWidth)/float64(r.Width), float64(cellHeight)/float64(r.Height)))
+	return Rectangle{Width: min(scaled.Width, cellWidth), Height: min(scaled.Height, cellHeight)}
+}
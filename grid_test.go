@@ -0,0 +1,124 @@
+package binpack_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackWithOptions_Grid verifies that rectangles are placed into cells in
+// row-major order.
+func TestPackWithOptions_Grid(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles of varying sizes.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 20},
+		{Width: 30, Height: 10},
+		{Width: 20, Height: 20},
+		{Width: 10, Height: 10},
+		{Width: 5, Height: 5},
+	})
+
+	// Act: pack the rectangles into a grid with two columns.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Heuristic: binpack.HeuristicGrid, Columns: 2})
+
+	// Assert: the cells should be sized to the largest rectangles.
+	require.NoError(t, err)
+	require.Equal(t, 60, layout.Width, "expected width 60")
+	require.Equal(t, 60, layout.Height, "expected height 60")
+
+	// Assert: each rectangle should be at the top-left corner of its cell.
+	expected := []struct{ x, y int }{{0, 0}, {30, 0}, {0, 20}, {30, 20}, {0, 40}}
+	for i, e := range expected {
+		require.Equal(t, e.x, tp.placements[i].x, "expected x-coordinate %d for rectangle %d", e.x, i)
+		require.Equal(t, e.y, tp.placements[i].y, "expected y-coordinate %d for rectangle %d", e.y, i)
+	}
+}
+
+// TestPackWithOptions_GridCentered verifies that rectangles are centered within
+// fixed size cells.
+func TestPackWithOptions_GridCentered(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles smaller than the cells.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 80, Height: 20},
+	})
+
+	// Act: pack the rectangles into centered 100x100 cells.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{
+		Heuristic:    binpack.HeuristicGrid,
+		Columns:      2,
+		CellWidth:    100,
+		CellHeight:   100,
+		CenterInCell: true,
+	})
+
+	// Assert: each rectangle should be centered within its cell.
+	require.NoError(t, err)
+	require.Equal(t, 200, layout.Width, "expected width 200")
+	require.Equal(t, 100, layout.Height, "expected height 100")
+	require.Equal(t, 25, tp.placements[0].x, "expected x-coordinate 25")
+	require.Equal(t, 25, tp.placements[0].y, "expected y-coordinate 25")
+	require.Equal(t, 110, tp.placements[1].x, "expected x-coordinate 110")
+	require.Equal(t, 40, tp.placements[1].y, "expected y-coordinate 40")
+}
+
+// TestPackWithOptions_GridCells verifies that the cell of each placement is
+// reported alongside its centered position.
+func TestPackWithOptions_GridCells(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: two rectangles smaller than the cells.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 80, Height: 20},
+	})
+
+	// Act: pack them centered in a grid inside an edge margin.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{
+		Heuristic:    binpack.HeuristicGrid,
+		Columns:      2,
+		CellWidth:    100,
+		CellHeight:   100,
+		CenterInCell: true,
+		EdgeMargin:   5,
+	})
+
+	// Assert: the offset within each cell is the centering.
+	require.NoError(t, err)
+	require.Equal(t, []image.Rectangle{image.Rect(5, 5, 105, 105), image.Rect(105, 5, 205, 105)}, layout.Cells)
+	require.Equal(t, image.Pt(25, 25), image.Pt(layout.Placements[0].X, layout.Placements[0].Y).Sub(layout.Cells[0].Min))
+	require.Equal(t, image.Pt(10, 40), image.Pt(layout.Placements[1].X, layout.Placements[1].Y).Sub(layout.Cells[1].Min))
+}
+
+// TestPackWithOptions_GridOversized verifies that rectangles larger than a
+// cell are scaled down to fit it, keeping their proportions.
+func TestPackWithOptions_GridOversized(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a rectangle that is larger than the cells.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 150, Height: 50},
+	})
+	opts := binpack.Options{
+		Heuristic:  binpack.HeuristicGrid,
+		CellWidth:  100,
+		CellHeight: 100,
+	}
+
+	// Act: pack the rectangles into 100x100 cells.
+	layout, err := binpack.PackWithOptions(tp, opts)
+
+	// Assert: the oversized rectangle should be scaled to the width of its cell.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, 2)
+	require.Equal(t, binpack.Placement{Index: 0, X: 0, Y: 0, Width: 50, Height: 50}, layout.Placements[0])
+	require.Equal(t, binpack.Placement{Index: 1, X: 100, Y: 0, Width: 100, Height: 33}, layout.Placements[1])
+	require.NoError(t, binpack.ValidateLayoutWithOptions(tp, layout, opts))
+}
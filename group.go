@@ -0,0 +1,82 @@
+package binpack
+
+import "math"
+
+// Unplaced is the coordinate passed to Place for rectangles that lost to an
+// alternative in their exclusive group.
+const Unplaced = math.MinInt
+
+// ExclusiveGroup is an optional interface for Packables that supply
+// alternative shapes for the same item, such as both orientations of an
+// image. Rectangles that share a group compete for a single placement when
+// the largest of them is reached: only the alternative that places best is
+// kept, and the others are placed at (Unplaced, Unplaced).
+type ExclusiveGroup interface {
+	// Group returns the group of the rectangle at index n, or false if the
+	// rectangle does not belong to a group.
+	Group(n int) (group int, ok bool)
+}
+
+// collectGroups returns the members of every exclusive group in p, keyed by
+// the index of each member. Members are listed in ascending order.
+func collectGroups(p Packable) map[int][]int {
+	var g, ok = optional[ExclusiveGroup](p)
+	if !ok {
+		return nil
+	}
+
+	// Gather the members of each group, leaving out the gaps.
+	var skip = skipper(p)
+	var members = make(map[int][]int)
+	for i := 0; i < p.Len(); i++ {
+		if group, ok := g.Group(i); ok && !skip(i) {
+			members[group] = append(members[group], i)
+		}
+	}
+
+	// Index the members by each of the rectangles in the group.
+	var groups = make(map[int][]int)
+	for _, alternatives := range members {
+		for _, alternative := range alternatives {
+			groups[alternative] = alternatives
+		}
+	}
+	return groups
+}
+
+// chooseAlternative locates each of the alternatives and returns the one whose
+// placement produces the best score, along with its rectangle and position.
+// b is the bounding box of the existing placements.
+// Ties are broken by the lowest index. Returns false if none of the
+// alternatives can be placed.
+func chooseAlternative(p Packable, alternatives []int, placements []placement, candidates *candidateSet, b bounds, opts Options) (int, Rectangle, int, int, bool) {
+	var best, bestX, bestY int
+	var bestRectangle Rectangle
+	var bestRank rank
+	var found = false
+	for _, alternative := range alternatives {
+		var rectangle = p.Rectangle(alternative)
+		var c = constraints{allowed: allowedAt(p, alternative)}
+		if radii := cornerRadii(p); radii != nil {
+			c.radius, c.radii = radii(alternative), radii
+		}
+		var x, y, ok = locate(rectangle, placements, candidates, b, c, opts)
+		if !ok {
+			continue
+		}
+
+		var candidate = placement{
+			x:      x,
+			y:      y,
+			width:  rectangle.Width,
+			height: rectangle.Height,
+		}
+		var candidateRank = rankCandidate(candidate, b, expandBoundsForPlacement(candidate, b), c, opts)
+		if !found || candidateRank.less(bestRank) {
+			best, bestRectangle, bestX, bestY, bestRank = alternative, rectangle, x, y, candidateRank
+			found = true
+		}
+	}
+
+	return best, bestRectangle, bestX, bestY, found
+}
@@ -0,0 +1,102 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testGroupPackable extends testPackable with exclusive groups.
+type testGroupPackable struct {
+	*testPackable
+	groups map[int]int
+}
+
+// Ensure that testGroupPackable implements the binpack.ExclusiveGroup interface.
+var _ binpack.ExclusiveGroup = (*testGroupPackable)(nil)
+
+// Group returns the group of the rectangle at the specified index.
+func (tp *testGroupPackable) Group(n int) (int, bool) {
+	group, ok := tp.groups[n]
+	return group, ok
+}
+
+// TestPackWithOptions_ExclusiveGroup verifies that only the best placing
+// alternative in a group is kept.
+func TestPackWithOptions_ExclusiveGroup(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a block and an item supplied in both orientations.
+	tp := &testGroupPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 100, Height: 100},
+			{Width: 20, Height: 100},
+			{Width: 100, Height: 20},
+		}),
+		groups: map[int]int{1: 0, 2: 0},
+	}
+
+	// Act: pack the rectangles into a space that only fits the landscape orientation.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{MaxWidth: 100})
+
+	// Assert: the landscape orientation should be kept.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, 2)
+	require.Equal(t, 0, tp.placements[2].x, "expected x-coordinate 0")
+	require.Equal(t, 100, tp.placements[2].y, "expected y-coordinate 100")
+
+	// Assert: the portrait orientation should be placed at the sentinel.
+	require.Equal(t, binpack.Unplaced, tp.placements[1].x, "expected unplaced x-coordinate")
+	require.Equal(t, binpack.Unplaced, tp.placements[1].y, "expected unplaced y-coordinate")
+}
+
+// TestPackWithOptions_ExclusiveGroupBestScore verifies that the alternative
+// producing the smallest layout is kept.
+func TestPackWithOptions_ExclusiveGroupBestScore(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a block and an item with a large and a small alternative.
+	tp := &testGroupPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 50, Height: 100},
+			{Width: 50, Height: 50},
+			{Width: 10, Height: 10},
+		}),
+		groups: map[int]int{1: 7, 2: 7},
+	}
+
+	// Act: pack the rectangles.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: the small alternative should be kept.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, 2)
+	require.Equal(t, 50, layout.Width, "expected width 50")
+	require.Equal(t, 110, layout.Height, "expected height 110")
+	require.Equal(t, binpack.Unplaced, tp.placements[1].x, "expected unplaced x-coordinate")
+	require.NotEqual(t, binpack.Unplaced, tp.placements[2].x, "expected placed x-coordinate")
+}
+
+// TestPackBestEffort_ExclusiveGroupUnplaced verifies that every alternative is
+// reported as unplaced when none of them fit.
+func TestPackBestEffort_ExclusiveGroupUnplaced(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a block and an item where neither alternative fits.
+	tp := &testGroupPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 100, Height: 100},
+			{Width: 200, Height: 20},
+			{Width: 20, Height: 200},
+		}),
+		groups: map[int]int{1: 0, 2: 0},
+	}
+
+	// Act: pack the rectangles into a space that only fits the block.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{MaxWidth: 100, MaxHeight: 100})
+
+	// Assert: both alternatives should be unplaced.
+	require.Equal(t, []int{1, 2}, unplaced)
+	require.Len(t, layout.Placements, 1)
+}
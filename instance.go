@@ -0,0 +1,105 @@
+package binpack
+
+import "sort"
+
+// Hasher is an optional interface for Packables with repeated items, such as
+// the identical tiles of a tileset. Rectangles with the same hash and the same
+// dimensions are packed once: the rectangle with the lowest index is placed,
+// and every other instance is listed in Layout.Refs and placed at the same
+// position, so they all share one region of the layout.
+type Hasher interface {
+	// Hash returns the hash of the contents of the rectangle at index n.
+	Hash(n int) uint64
+}
+
+// PlacementRef records a rectangle that shares the placement of another
+// because both have the same hash.
+type PlacementRef struct {
+	// Index is the index of the repeated rectangle in the Packable.
+	Index int
+	// Ref is the index of the rectangle whose placement it shares.
+	Ref int
+}
+
+// collectInstances returns the repeated rectangles in p, each referring to the
+// first rectangle with the same hash and dimensions, in ascending order of
+// index. Returns nil if p does not implement Hasher.
+func collectInstances(p Packable) []PlacementRef {
+	var h, ok = optional[Hasher](p)
+	if !ok {
+		return nil
+	}
+
+	type key struct {
+		hash      uint64
+		rectangle Rectangle
+	}
+	var skip = skipper(p)
+	var first = make(map[key]int)
+	var refs []PlacementRef
+	for i := 0; i < p.Len(); i++ {
+		if skip(i) {
+			continue
+		}
+		var k = key{hash: h.Hash(i), rectangle: p.Rectangle(i)}
+		if ref, ok := first[k]; ok {
+			refs = append(refs, PlacementRef{Index: i, Ref: ref})
+			continue
+		}
+		first[k] = i
+	}
+	return refs
+}
+
+// packInstances computes the layout for p with each repeated rectangle
+// packed once, listing the repeats in the Refs of the layout. Repeats share
+// the fate of the rectangle they refer to: they are left unplaced, dropped or
+// excluded along with it.
+func packInstances(p Packable, opts Options, refs []PlacementRef) (Layout, []int, []int) {
+	var repeated = make(map[int]bool, len(refs))
+	for _, ref := range refs {
+		repeated[ref.Index] = true
+	}
+	var layout, unplaced, dropped = pack(&instances{forwarded: forwarded{p}, repeated: repeated}, opts)
+
+	var placed = make(map[int]bool, len(layout.Placements))
+	for _, placement := range layout.Placements {
+		placed[placement.Index] = true
+	}
+	var excluded = make(map[int]bool, len(layout.excluded))
+	for _, n := range layout.excluded {
+		excluded[n] = true
+	}
+	var missing = make(map[int]bool, len(unplaced))
+	for _, n := range unplaced {
+		missing[n] = true
+	}
+	for _, ref := range refs {
+		switch {
+		case placed[ref.Ref]:
+			layout.Refs = append(layout.Refs, ref)
+		case excluded[ref.Ref]:
+			layout.excluded = append(layout.excluded, ref.Index)
+		case missing[ref.Ref]:
+			unplaced = append(unplaced, ref.Index)
+		default:
+			dropped = append(dropped, ref.Index)
+		}
+	}
+	sort.Ints(layout.excluded)
+	sort.Ints(unplaced)
+	sort.Ints(dropped)
+	return layout, unplaced, dropped
+}
+
+// instances hides the repeated rectangles of a Packable by skipping them.
+type instances struct {
+	forwarded
+	repeated map[int]bool
+}
+
+// Skip returns true for the gaps of the wrapped Packable and for repeated
+// rectangles.
+func (s *instances) Skip(n int) bool {
+	return s.repeated[n] || s.forwarded.Skip(n)
+}
@@ -0,0 +1,76 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testHashPackable extends testPackable with a hash for each rectangle.
+type testHashPackable struct {
+	*testPackable
+	hashes []uint64
+}
+
+// Ensure that testHashPackable implements the binpack.Hasher interface.
+var _ binpack.Hasher = (*testHashPackable)(nil)
+
+// Hash returns the hash of the rectangle at the specified index.
+func (tp *testHashPackable) Hash(n int) uint64 {
+	return tp.hashes[n]
+}
+
+// TestHasher_Pack verifies that rectangles with the same hash and dimensions
+// are packed once and share a placement.
+func TestHasher_Pack(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create three identical tiles, and a fourth with the same hash
+	// but a different size.
+	tp := &testHashPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 10, Height: 10},
+			{Width: 20, Height: 10},
+			{Width: 10, Height: 10},
+			{Width: 10, Height: 10},
+			{Width: 5, Height: 5},
+		}),
+		hashes: []uint64{1, 2, 1, 1, 1},
+	}
+
+	// Act: pack the rectangles.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: the repeated tiles should share the placement of the first.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, 3)
+	require.Equal(t, []binpack.PlacementRef{{Index: 2, Ref: 0}, {Index: 3, Ref: 0}}, layout.Refs)
+	require.Equal(t, tp.placements[0], tp.placements[2])
+	require.Equal(t, tp.placements[0], tp.placements[3])
+	requireNoOverlap(t, layout)
+}
+
+// TestHasher_Unplaced verifies that repeats of a rectangle that does not fit
+// are left unplaced with it.
+func TestHasher_Unplaced(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a tile that is too wide, repeated twice.
+	tp := &testHashPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 10, Height: 10},
+			{Width: 50, Height: 10},
+			{Width: 50, Height: 10},
+		}),
+		hashes: []uint64{1, 2, 2},
+	}
+
+	// Act: pack the rectangles within a narrow width.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{MaxWidth: 20})
+
+	// Assert: both copies of the wide tile should be unplaced.
+	require.Equal(t, []int{1, 2}, unplaced)
+	require.Len(t, layout.Placements, 1)
+	require.Empty(t, layout.Refs)
+}
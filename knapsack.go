@@ -0,0 +1,65 @@
+package binpack
+
+import (
+	"math"
+	"sort"
+)
+
+// Weigher is an optional interface for Packables whose rectangles have an
+// importance used by PackKnapsack.
+type Weigher interface {
+	// Weight returns the importance of the rectangle at index n.
+	Weight(n int) int
+}
+
+// PackKnapsack chooses a subset of the rectangles in p that fits within a
+// binW x binH bin and has a high total weight, places them, and returns their
+// indices in ascending order. Rectangles are tried greedily in order of
+// weight per unit of area, and each is kept if it fits alongside those
+// already chosen. If p does not implement Weigher, each rectangle weighs its
+// area. Rectangles with no weight are never chosen.
+func PackKnapsack(p Packable, binW, binH int) []int {
+	if binW <= 0 || binH <= 0 {
+		return nil
+	}
+
+	var weigher, _ = optional[Weigher](p)
+	var weight = func(n int) int {
+		if weigher == nil {
+			return p.Rectangle(n).Area()
+		}
+		return weigher.Weight(n)
+	}
+
+	// Order the weighted rectangles by density, breaking ties by the larger weight.
+	var positions []int
+	var density = make(map[int]float64)
+	for i := 0; i < p.Len(); i++ {
+		if weight(i) <= 0 {
+			continue
+		}
+		positions = append(positions, i)
+		if area := p.Rectangle(i).area64(); area > 0 {
+			density[i] = float64(weight(i)) / float64(area)
+		} else {
+			density[i] = math.Inf(1)
+		}
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		var a, b = positions[i], positions[j]
+		if density[a] != density[b] {
+			return density[a] > density[b]
+		}
+		return weight(a) > weight(b)
+	})
+
+	var layout, _, _ = packOrdered(p, Options{MaxWidth: binW, MaxHeight: binH}, positions, nil, bounds{})
+	layout.apply(p)
+
+	var chosen = make([]int, 0, len(layout.Placements))
+	for _, placement := range layout.Placements {
+		chosen = append(chosen, placement.Index)
+	}
+	sort.Ints(chosen)
+	return chosen
+}
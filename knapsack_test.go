@@ -0,0 +1,77 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testWeightPackable extends testPackable with weights.
+type testWeightPackable struct {
+	*testPackable
+	weights []int
+}
+
+// Ensure that testWeightPackable implements the binpack.Weigher interface.
+var _ binpack.Weigher = (*testWeightPackable)(nil)
+
+// Weight returns the weight of the rectangle at the specified index.
+func (tp *testWeightPackable) Weight(n int) int {
+	return tp.weights[n]
+}
+
+// TestPackKnapsack_Weights verifies that the densest rectangles are chosen
+// when not everything fits.
+func TestPackKnapsack_Weights(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles where the largest is the least important.
+	tp := &testWeightPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 100, Height: 100},
+			{Width: 50, Height: 100},
+			{Width: 50, Height: 100},
+			{Width: 50, Height: 50},
+		}),
+		weights: []int{10, 20, 30, 0},
+	}
+
+	// Act: choose the rectangles that fit a 100x100 banner.
+	chosen := binpack.PackKnapsack(tp, 100, 100)
+
+	// Assert: the two important halves should be chosen and placed side by side.
+	require.Equal(t, []int{1, 2}, chosen)
+	require.NotEqual(t, tp.placements[1].x, tp.placements[2].x)
+	require.Equal(t, tp.placements[1].y, tp.placements[2].y)
+}
+
+// TestPackKnapsack_Area verifies that rectangles weigh their area when no
+// weights are supplied.
+func TestPackKnapsack_Area(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles that cannot all fit.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 60, Height: 60},
+		{Width: 100, Height: 100},
+		{Width: 50, Height: 50},
+	})
+
+	// Act: choose the rectangles that fit a 100x100 bin.
+	chosen := binpack.PackKnapsack(tp, 100, 100)
+
+	// Assert: the rectangle that fills the bin should be chosen.
+	require.Equal(t, []int{1}, chosen)
+}
+
+// TestPackKnapsack_EmptyBin verifies that nothing is chosen for an empty bin.
+func TestPackKnapsack_EmptyBin(t *testing.T) {
+	t.Parallel()
+
+	// Act: choose rectangles for a bin with no area.
+	chosen := binpack.PackKnapsack(newTestPackable([]binpack.Rectangle{{Width: 1, Height: 1}}), 0, 10)
+
+	// Assert: nothing should be chosen.
+	require.Empty(t, chosen)
+}
@@ -0,0 +1,598 @@
+package binpack
+
+import (
+	"image"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Placement represents the position of a rectangle within a Layout.
+type Placement struct {
+	// Index is the index of the rectangle in the Packable.
+	Index int
+	// X and Y are the coordinates of the top-left corner of the rectangle.
+	X, Y int
+	// Width and Height are the dimensions of the rectangle.
+	Width, Height int
+}
+
+// Layout represents the result of packing a set of rectangles.
+type Layout struct {
+	// Width and Height are the overall dimensions of the layout.
+	Width, Height int
+	// Placements holds the position of every placed rectangle, in the order
+	// set by Options.OrderBy.
+	Placements []Placement
+	// Overlaps lists the rectangles that overlap, which only happens when
+	// Options.MaxOverlapRatio allows it.
+	Overlaps []OverlapPair
+	// Cells holds the grid cell allotted to each placement, in the same
+	// order as Placements, when packed with HeuristicGrid. The offset of a
+	// rectangle centered by Options.CenterInCell is its position less the
+	// origin of its cell. Nil for other heuristics.
+	Cells []image.Rectangle
+	// Refs lists the rectangles that share the placement of another because
+	// the Packable implements Hasher and both have the same hash. They are
+	// not included in Placements.
+	Refs []PlacementRef
+	// Backgrounds lists the background rectangles of a Backgrounder in the
+	// order they were placed. Draw them before the other placements, which
+	// may overlap them.
+	Backgrounds []int
+
+	// excluded holds the rectangles that lost to an alternative in their
+	// exclusive group.
+	excluded []int
+}
+
+// Contains returns true if inner lies entirely within p, including when
+// they share edges, such as to detect layouts nested in others.
+func (p Placement) Contains(inner Placement) bool {
+	return containsPlacement(newPlacement(p), newPlacement(inner))
+}
+
+// ContainsPoint returns true if the point (x, y) lies within p. Placements
+// include their top-left edges but not their bottom-right edges.
+func (p Placement) ContainsPoint(x, y int) bool {
+	return Rectangle{Width: p.Width, Height: p.Height}.Contains(x-p.X, y-p.Y)
+}
+
+// At returns the index of the rectangle whose placement contains the point
+// (x, y), or false if no placement contains it. Placements include their
+// top-left edges but not their bottom-right edges.
+func (l Layout) At(x, y int) (index int, ok bool) {
+	for _, p := range l.Placements {
+		if p.ContainsPoint(x, y) {
+			return p.Index, true
+		}
+	}
+	return 0, false
+}
+
+// OccupancyGrid returns a grid of cell x cell squares covering the layout,
+// indexed by row and then column, in which each cell is true if any placement
+// covers part of it. Use OccupancyGridStrict to count only cells that are
+// entirely covered. Returns nil if cell is not positive.
+func OccupancyGrid(layout Layout, cell int) [][]bool {
+	if cell <= 0 {
+		return nil
+	}
+	var grid = make([][]bool, (layout.Height+cell-1)/cell)
+	for row := range grid {
+		grid[row] = make([]bool, (layout.Width+cell-1)/cell)
+	}
+	for _, p := range layout.Placements {
+		if p.Width <= 0 || p.Height <= 0 {
+			continue
+		}
+		for row := max(p.Y/cell, 0); row <= (p.Y+p.Height-1)/cell && row < len(grid); row++ {
+			for col := max(p.X/cell, 0); col <= (p.X+p.Width-1)/cell && col < len(grid[row]); col++ {
+				grid[row][col] = true
+			}
+		}
+	}
+	return grid
+}
+
+// OccupancyGridStrict is like OccupancyGrid, but a cell is only true if the
+// placements cover all of it. Cells on the right and bottom edges of the
+// layout only need to be covered up to its dimensions.
+func OccupancyGridStrict(layout Layout, cell int) [][]bool {
+	var grid = OccupancyGrid(layout, cell)
+	var rects = make([]image.Rectangle, 0, len(layout.Placements))
+	for _, p := range layout.Placements {
+		rects = append(rects, image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height))
+	}
+	var bounds = image.Rect(0, 0, layout.Width, layout.Height)
+	for row := range grid {
+		for col := range grid[row] {
+			var r = image.Rect(col*cell, row*cell, (col+1)*cell, (row+1)*cell).Intersect(bounds)
+			grid[row][col] = grid[row][col] && covers(rects, r)
+		}
+	}
+	return grid
+}
+
+// AdjacencyGraph returns, for the index of every placed rectangle, the sorted
+// indices of the rectangles it shares an edge with. Edges must touch along a
+// nonzero length, so rectangles that only meet at a corner are not adjacent,
+// and rectangles with no area have no neighbours.
+func AdjacencyGraph(layout Layout) map[int][]int {
+	var graph = make(map[int][]int, len(layout.Placements))
+	for i, a := range layout.Placements {
+		if a.Width <= 0 || a.Height <= 0 {
+			continue
+		}
+		if _, ok := graph[a.Index]; !ok {
+			graph[a.Index] = []int{}
+		}
+		for _, b := range layout.Placements[i+1:] {
+			if b.Width <= 0 || b.Height <= 0 || !adjacent(a, b) {
+				continue
+			}
+			graph[a.Index] = append(graph[a.Index], b.Index)
+			graph[b.Index] = append(graph[b.Index], a.Index)
+		}
+	}
+	for _, neighbours := range graph {
+		sort.Ints(neighbours)
+	}
+	return graph
+}
+
+// adjacent returns true if a and b touch along an edge of nonzero length.
+func adjacent(a, b Placement) bool {
+	var overlapX = min(a.X+a.Width, b.X+b.Width) - max(a.X, b.X)
+	var overlapY = min(a.Y+a.Height, b.Y+b.Height) - max(a.Y, b.Y)
+	var touchX = a.X+a.Width == b.X || b.X+b.Width == a.X
+	var touchY = a.Y+a.Height == b.Y || b.Y+b.Height == a.Y
+	return (touchX && overlapY > 0) || (touchY && overlapX > 0)
+}
+
+// Rotate90 returns a copy of l rotated by 90 degrees clockwise, such as to
+// turn a landscape layout into a portrait one. The overall dimensions are
+// swapped, and so are those of every placement, so each rectangle must be
+// drawn rotated too. Rectangles with no area stay at the origin.
+func (l Layout) Rotate90() Layout {
+	var rotated = l
+	rotated.Width, rotated.Height = l.Height, l.Width
+	rotated.Placements = make([]Placement, len(l.Placements))
+	for i, p := range l.Placements {
+		rotated.Placements[i] = Placement{Index: p.Index, Width: p.Height, Height: p.Width}
+		if p.Width > 0 && p.Height > 0 {
+			rotated.Placements[i].X = l.Height - p.Y - p.Height
+			rotated.Placements[i].Y = p.X
+		}
+	}
+	if l.Cells != nil {
+		rotated.Cells = make([]image.Rectangle, len(l.Cells))
+		for i, c := range l.Cells {
+			rotated.Cells[i] = image.Rect(l.Height-c.Max.Y, c.Min.X, l.Height-c.Min.Y, c.Max.X)
+		}
+	}
+	return rotated
+}
+
+// flipY converts the placements to coordinates whose y-axis grows upward from
+// the bottom of the layout. Rectangles with no area stay at the origin.
+func (l Layout) flipY() {
+	for i, p := range l.Placements {
+		if p.Width > 0 && p.Height > 0 {
+			l.Placements[i].Y = l.Height - p.Y - p.Height
+		}
+	}
+	for i, c := range l.Cells {
+		l.Cells[i] = image.Rect(c.Min.X, l.Height-c.Max.Y, c.Max.X, l.Height-c.Min.Y)
+	}
+}
+
+// apply places every rectangle in the layout on p.
+func (l Layout) apply(p Packable) {
+	var flexible, _ = optional[Flexible](p)
+	for _, placement := range l.Placements {
+		if flexible != nil && resized(p.Rectangle(placement.Index), placement) {
+			flexible.Resize(placement.Index, placement.Width, placement.Height)
+		}
+		p.Place(placement.Index, placement.X, placement.Y)
+	}
+	if len(l.Refs) > 0 {
+		var positions = make(map[int]image.Point, len(l.Placements))
+		for _, placement := range l.Placements {
+			positions[placement.Index] = image.Pt(placement.X, placement.Y)
+		}
+		for _, ref := range l.Refs {
+			var position = positions[ref.Ref]
+			p.Place(ref.Index, position.X, position.Y)
+		}
+	}
+	for _, n := range l.excluded {
+		p.Place(n, Unplaced, Unplaced)
+	}
+}
+
+// resized returns true if the placement has a different size than r in
+// either orientation.
+func resized(r Rectangle, p Placement) bool {
+	return (r.Width != p.Width || r.Height != p.Height) && (r.Width != p.Height || r.Height != p.Width)
+}
+
+// ScaleLayout returns a copy of l with all coordinates and dimensions
+// multiplied by factor. Dimensions are rounded the same way as Rectangle.Scale.
+// Rounding can introduce sub-pixel overlaps between neighbours, so any
+// placement that would overlap an earlier one is nudged right or down until it
+// is clear, and the overall dimensions grow to contain it. The foreground may
+// still overlap the backgrounds, and the pairs listed in l.Overlaps may still
+// overlap each other, with their shared area measured again. The cells are
+// scaled with their corners rounded to the nearest integer.
+func ScaleLayout(l Layout, factor float64) Layout {
+	var scaled = Layout{
+		Width:       int(math.Round(float64(l.Width) * factor)),
+		Height:      int(math.Round(float64(l.Height) * factor)),
+		Placements:  make([]Placement, 0, len(l.Placements)),
+		Refs:        l.Refs,
+		Backgrounds: l.Backgrounds,
+		excluded:    l.excluded,
+	}
+
+	// Visit the placements from left to right so nudges move away from settled placements.
+	var order = make([]int, len(l.Placements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		var a, b = l.Placements[order[i]], l.Placements[order[j]]
+		return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+	})
+
+	var background = make(map[int]bool, len(l.Backgrounds))
+	for _, n := range l.Backgrounds {
+		background[n] = true
+	}
+	var allowed = make(map[[2]int]bool, len(l.Overlaps))
+	for _, pair := range l.Overlaps {
+		allowed[[2]int{min(pair.A, pair.B), max(pair.A, pair.B)}] = true
+	}
+	var settled = make([]placement, 0, len(l.Placements))
+	var positions = make([]Placement, len(l.Placements))
+	for _, i := range order {
+		var p = l.Placements[i]
+		var size = Rectangle{Width: p.Width, Height: p.Height}.Scale(factor)
+		var candidate = placement{
+			position: p.Index,
+			x:        int(math.Round(float64(p.X) * factor)),
+			y:        int(math.Round(float64(p.Y) * factor)),
+			width:    size.Width,
+			height:   size.Height,
+		}
+
+		// Nudge the candidate right or down, whichever clears the overlap sooner.
+		// Each move passes an edge of a settled placement, so the loop terminates.
+		for nudged := true; nudged; {
+			nudged = false
+			for _, other := range settled {
+				if background[other.position] != background[candidate.position] || !doRectanglesIntersect(candidate, other) {
+					continue
+				}
+				if allowed[[2]int{min(other.position, candidate.position), max(other.position, candidate.position)}] {
+					continue
+				}
+				var overlapX = other.x + other.width - candidate.x
+				var overlapY = other.y + other.height - candidate.y
+				if overlapX <= overlapY {
+					candidate.x += overlapX
+				} else {
+					candidate.y += overlapY
+				}
+				nudged = true
+			}
+		}
+		settled = append(settled, candidate)
+
+		// Grow the overall dimensions to contain the placement.
+		scaled.Width = max(scaled.Width, candidate.x+candidate.width)
+		scaled.Height = max(scaled.Height, candidate.y+candidate.height)
+		positions[i] = Placement{
+			Index:  candidate.position,
+			X:      candidate.x,
+			Y:      candidate.y,
+			Width:  candidate.width,
+			Height: candidate.height,
+		}
+	}
+	scaled.Placements = append(scaled.Placements, positions...)
+
+	// Measure the allowed overlaps again, as the shared areas are scaled and rounded.
+	if l.Overlaps != nil {
+		var placed = make(map[int]Placement, len(positions))
+		for _, p := range positions {
+			placed[p.Index] = p
+		}
+		scaled.Overlaps = make([]OverlapPair, 0, len(l.Overlaps))
+		for _, pair := range l.Overlaps {
+			var a, b = placed[pair.A], placed[pair.B]
+			var shared = image.Rect(a.X, a.Y, a.X+a.Width, a.Y+a.Height).Intersect(image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height))
+			scaled.Overlaps = append(scaled.Overlaps, OverlapPair{A: pair.A, B: pair.B, Area: int64(shared.Dx()) * int64(shared.Dy())})
+		}
+	}
+	if l.Cells != nil {
+		var scale = func(v int) int {
+			return int(math.Round(float64(v) * factor))
+		}
+		scaled.Cells = make([]image.Rectangle, len(l.Cells))
+		for i, c := range l.Cells {
+			scaled.Cells[i] = image.Rect(scale(c.Min.X), scale(c.Min.Y), scale(c.Max.X), scale(c.Max.Y))
+		}
+	}
+	return scaled
+}
+
+// FitInto returns a copy of l scaled uniformly by ScaleLayout to fit within a
+// boxW x boxH box, such as for a preview thumbnail, along with the factor
+// used. Layouts that already fit are not enlarged; use FitIntoUpscaled to
+// fill the box instead.
+func (l Layout) FitInto(boxW, boxH int) (Layout, float64) {
+	return l.fitInto(boxW, boxH, false)
+}
+
+// FitIntoUpscaled is like FitInto, but enlarges layouts that are smaller than
+// the box so that they fill it.
+func (l Layout) FitIntoUpscaled(boxW, boxH int) (Layout, float64) {
+	return l.fitInto(boxW, boxH, true)
+}
+
+// fitInto implements FitInto and FitIntoUpscaled.
+func (l Layout) fitInto(boxW, boxH int, upscale bool) (Layout, float64) {
+	if l.Width <= 0 || l.Height <= 0 {
+		return l, 1
+	}
+	var factor = max(min(float64(boxW)/float64(l.Width), float64(boxH)/float64(l.Height)), 0)
+	if !upscale {
+		factor = min(factor, 1)
+	}
+
+	// Rounding and nudging can push the scaled layout past the box, so shrink the factor until it fits.
+	var scaled = ScaleLayout(l, factor)
+	for scaled.Width > boxW || scaled.Height > boxH {
+		factor *= min(float64(boxW)/float64(scaled.Width), float64(boxH)/float64(scaled.Height), 0.999)
+		scaled = ScaleLayout(l, factor)
+	}
+	return scaled, factor
+}
+
+// Direction selects how Combine arranges layouts.
+type Direction int
+
+const (
+	// DirectionHorizontal places the layouts side by side from left to
+	// right, aligned to the top. This is the default.
+	DirectionHorizontal Direction = iota
+	// DirectionVertical stacks the layouts from top to bottom, aligned to
+	// the left.
+	DirectionVertical
+)
+
+// Combine arranges independently packed layouts side by side or stacked, as
+// set by direction, with gap between neighbours, and returns a single layout
+// holding all of their placements. Layouts with no area are left out. The
+// placements are renumbered so they stay distinct: the indices of each layout
+// are offset by one more than the highest index of the layouts before it. The
+// cells of grid layouts are moved with their placements; if only some of the
+// layouts have cells, each placement of the others is its own cell.
+func Combine(layouts []Layout, gap int, direction Direction) Layout {
+	var combined Layout
+	var offset, next int
+	var first = true
+	var celled = slices.ContainsFunc(layouts, func(l Layout) bool { return l.Cells != nil && l.Width > 0 && l.Height > 0 })
+	for _, l := range layouts {
+		if l.Width <= 0 || l.Height <= 0 {
+			continue
+		}
+
+		// Find where this layout starts, and size the combined layout to hold it.
+		var dx, dy int
+		if direction == DirectionVertical {
+			if !first {
+				dy = combined.Height + gap
+			}
+			combined.Width = max(combined.Width, l.Width)
+			combined.Height = dy + l.Height
+		} else {
+			if !first {
+				dx = combined.Width + gap
+			}
+			combined.Width = dx + l.Width
+			combined.Height = max(combined.Height, l.Height)
+		}
+
+		for _, p := range l.Placements {
+			p.Index += offset
+			p.X += dx
+			p.Y += dy
+			combined.Placements = append(combined.Placements, p)
+			next = max(next, p.Index+1)
+		}
+		if celled {
+			for i, p := range l.Placements {
+				var cell = image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height)
+				if i < len(l.Cells) {
+					cell = l.Cells[i]
+				}
+				combined.Cells = append(combined.Cells, cell.Add(image.Pt(dx, dy)))
+			}
+		}
+		for _, o := range l.Overlaps {
+			combined.Overlaps = append(combined.Overlaps, OverlapPair{A: o.A + offset, B: o.B + offset, Area: o.Area})
+		}
+		for _, ref := range l.Refs {
+			combined.Refs = append(combined.Refs, PlacementRef{Index: ref.Index + offset, Ref: ref.Ref + offset})
+			next = max(next, ref.Index+offset+1)
+		}
+		for _, n := range l.Backgrounds {
+			combined.Backgrounds = append(combined.Backgrounds, n+offset)
+		}
+		for _, n := range l.excluded {
+			combined.excluded = append(combined.excluded, n+offset)
+			next = max(next, n+offset+1)
+		}
+		offset = next
+		first = false
+	}
+	return combined
+}
+
+// AddToLayout places newRects into the free space of l without moving any of
+// its existing placements. The layout does not grow: rectangles that do not
+// fit within its dimensions are left out, and their indices in newRects are
+// returned in ascending order. The new rectangles are numbered after the
+// existing ones, so newRects[i] is placed with Index n+i, where n is one
+// greater than the highest Index in l.
+func AddToLayout(l Layout, newRects []Rectangle) (Layout, []int) {
+	var next int
+	var fixed = make([]placement, 0, len(l.Placements))
+	for _, p := range l.Placements {
+		fixed = append(fixed, placement{
+			position: p.Index,
+			x:        p.X,
+			y:        p.Y,
+			width:    p.Width,
+			height:   p.Height,
+		})
+		next = max(next, p.Index+1)
+	}
+
+	// An empty layout has no free space to fill.
+	if l.Width <= 0 || l.Height <= 0 {
+		var unplaced = make([]int, len(newRects))
+		for i := range unplaced {
+			unplaced[i] = i
+		}
+		return l, unplaced
+	}
+
+	// Pack the new rectangles around the existing placements without growing the layout,
+	// keeping the existing placements first so the new ones can be renumbered.
+	var opts = Options{MaxWidth: l.Width, MaxHeight: l.Height, OrderBy: OrderPlacement}
+	var layout, unplaced, _ = packAround(rectangleSlice(newRects), opts, fixed, bounds{maxX: l.Width, maxY: l.Height})
+	for i := len(fixed); i < len(layout.Placements); i++ {
+		layout.Placements[i].Index += next
+	}
+	layout.Width, layout.Height = l.Width, l.Height
+	layout.Refs = l.Refs
+	layout.Backgrounds = l.Backgrounds
+	layout.excluded = l.excluded
+	return layout, unplaced
+}
+
+// ResolveOverlaps moves the placements that overlap the one with index
+// movedIndex, such as after it has been dragged in an editor, to the nearest
+// positions where they overlap nothing, and returns the updated layout. Every
+// other placement, including the moved one, stays where it is. The
+// overlapping placements are moved in the order they appear in the layout,
+// to positions with non-negative coordinates beside the edges of the other
+// placements, and the dimensions of the layout grow to contain them. The
+// layout is returned unchanged if it has no placement with movedIndex.
+func ResolveOverlaps(layout Layout, movedIndex int) Layout {
+	var moved = slices.IndexFunc(layout.Placements, func(p Placement) bool { return p.Index == movedIndex })
+	if moved < 0 {
+		return layout
+	}
+	var toPlacement = func(p Placement) placement {
+		return placement{position: p.Index, x: p.X, y: p.Y, width: p.Width, height: p.Height}
+	}
+
+	// Split the placements into those that stay and those in conflict with the moved one.
+	var fixed []placement
+	var conflicts []int
+	for i, p := range layout.Placements {
+		if i != moved && p.Width > 0 && p.Height > 0 && doRectanglesIntersect(toPlacement(p), toPlacement(layout.Placements[moved])) {
+			conflicts = append(conflicts, i)
+			continue
+		}
+		if p.Width > 0 && p.Height > 0 {
+			fixed = append(fixed, toPlacement(p))
+		}
+	}
+	if len(conflicts) == 0 {
+		return layout
+	}
+
+	var resolved = layout
+	resolved.Placements = slices.Clone(layout.Placements)
+	for _, i := range conflicts {
+		var p = resolved.Placements[i]
+
+		// Try the positions beside every edge, and the current position along each axis.
+		var xs, ys = []int{0, p.X}, []int{0, p.Y}
+		for _, f := range fixed {
+			xs = append(xs, f.x+f.width, f.x-p.Width)
+			ys = append(ys, f.y+f.height, f.y-p.Height)
+		}
+
+		var best placement
+		var bestDistance int64 = -1
+		for _, y := range ys {
+			for _, x := range xs {
+				if x < 0 || y < 0 {
+					continue
+				}
+				var candidate = placement{position: p.Index, x: x, y: y, width: p.Width, height: p.Height}
+				var dx, dy = int64(x - p.X), int64(y - p.Y)
+				var distance = dx*dx + dy*dy
+				if bestDistance >= 0 && (distance > bestDistance || (distance == bestDistance && (y > best.y || (y == best.y && x >= best.x)))) {
+					continue
+				}
+				if hasIntersection(candidate, fixed, nil) {
+					continue
+				}
+				best, bestDistance = candidate, distance
+			}
+		}
+
+		resolved.Placements[i].X, resolved.Placements[i].Y = best.x, best.y
+		resolved.Width = max(resolved.Width, best.x+best.width)
+		resolved.Height = max(resolved.Height, best.y+best.height)
+		fixed = append(fixed, best)
+	}
+	return resolved
+}
+
+// Change describes a rectangle whose position differs between two layouts.
+type Change struct {
+	// Index is the index of the rectangle in the Packable.
+	Index int
+	// OldX and OldY are the coordinates of the rectangle in the old layout.
+	OldX, OldY int
+	// NewX and NewY are the coordinates of the rectangle in the new layout.
+	NewX, NewY int
+}
+
+// Diff returns the rectangles whose position changed between the old and new
+// layouts, matched by index and sorted by index. Rectangles that appear in
+// only one of the layouts are not reported.
+func Diff(old, new Layout) []Change {
+	var previous = make(map[int]Placement, len(old.Placements))
+	for _, p := range old.Placements {
+		previous[p.Index] = p
+	}
+
+	var changes []Change
+	for _, p := range new.Placements {
+		var o, ok = previous[p.Index]
+		if !ok || (o.X == p.X && o.Y == p.Y) {
+			continue
+		}
+		changes = append(changes, Change{
+			Index: p.Index,
+			OldX:  o.X,
+			OldY:  o.Y,
+			NewX:  p.X,
+			NewY:  p.Y,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Index < changes[j].Index
+	})
+	return changes
+}
@@ -0,0 +1,527 @@
+package binpack_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestScaleLayout_Double verifies that scaling by an integer factor multiplies
+// every coordinate and dimension exactly.
+func TestScaleLayout_Double(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pack a set of rectangles.
+	layout, err := binpack.PackWithOptions(newTestPackable([]binpack.Rectangle{
+		{Width: 100, Height: 200},
+		{Width: 50, Height: 50},
+		{Width: 80, Height: 120},
+	}), binpack.Options{})
+	require.NoError(t, err)
+
+	// Act: scale the layout by 2.
+	scaled := binpack.ScaleLayout(layout, 2)
+
+	// Assert: every coordinate and dimension should be doubled.
+	require.Equal(t, layout.Width*2, scaled.Width)
+	require.Equal(t, layout.Height*2, scaled.Height)
+	for i, p := range layout.Placements {
+		require.Equal(t, binpack.Placement{
+			Index:  p.Index,
+			X:      p.X * 2,
+			Y:      p.Y * 2,
+			Width:  p.Width * 2,
+			Height: p.Height * 2,
+		}, scaled.Placements[i])
+	}
+}
+
+// TestScaleLayout_Rounding verifies that placements are nudged apart when
+// rounding would make them overlap.
+func TestScaleLayout_Rounding(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout of adjacent one pixel rectangles.
+	layout := binpack.Layout{
+		Width:  3,
+		Height: 1,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 1, Height: 1},
+			{Index: 1, X: 1, Y: 0, Width: 1, Height: 1},
+			{Index: 2, X: 2, Y: 0, Width: 1, Height: 1},
+		},
+	}
+
+	// Act: scale the layout by a factor that rounds up.
+	scaled := binpack.ScaleLayout(layout, 1.5)
+
+	// Assert: dimensions should match Rectangle.Scale and nothing should overlap.
+	for _, p := range scaled.Placements {
+		require.Equal(t, binpack.Rectangle{Width: 1, Height: 1}.Scale(1.5), binpack.Rectangle{Width: p.Width, Height: p.Height})
+		require.LessOrEqual(t, p.X+p.Width, scaled.Width)
+		require.LessOrEqual(t, p.Y+p.Height, scaled.Height)
+	}
+	requireNoOverlap(t, scaled)
+}
+
+// TestScaleLayout_Cells verifies that the cells of a grid layout are scaled
+// with the placements.
+func TestScaleLayout_Cells(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pack two rectangles into 20x20 cells.
+	layout, err := binpack.PackWithOptions(newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 20, Height: 10},
+	}), binpack.Options{Heuristic: binpack.HeuristicGrid, Columns: 2, CellWidth: 20, CellHeight: 20})
+	require.NoError(t, err)
+
+	// Act: scale the layout by 1.5.
+	scaled := binpack.ScaleLayout(layout, 1.5)
+
+	// Assert: the cells should be scaled too.
+	require.Equal(t, []image.Rectangle{image.Rect(0, 0, 30, 30), image.Rect(30, 0, 60, 30)}, scaled.Cells)
+}
+
+// TestScaleLayout_Overlaps verifies that pairs allowed to overlap are not
+// nudged apart, and that their shared area is measured again.
+func TestScaleLayout_Overlaps(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: two rectangles that share a 5x10 strip.
+	layout := binpack.Layout{
+		Width:  15,
+		Height: 10,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 5, Y: 0, Width: 10, Height: 10},
+		},
+		Overlaps: []binpack.OverlapPair{{A: 0, B: 1, Area: 50}},
+	}
+
+	// Act: scale the layout by 2.
+	scaled := binpack.ScaleLayout(layout, 2)
+
+	// Assert: the rectangles should still overlap by the scaled strip.
+	require.Equal(t, binpack.Placement{Index: 1, X: 10, Y: 0, Width: 20, Height: 20}, scaled.Placements[1])
+	require.Equal(t, []binpack.OverlapPair{{A: 0, B: 1, Area: 200}}, scaled.Overlaps)
+	require.Equal(t, 30, scaled.Width)
+}
+
+// TestAddToLayout_FillsFreeSpace verifies that new rectangles are placed into
+// the gaps of an existing layout without moving the existing placements.
+func TestAddToLayout_FillsFreeSpace(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with a gap in the bottom-right corner.
+	layout := binpack.Layout{
+		Width:  100,
+		Height: 100,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 100, Height: 50},
+			{Index: 1, X: 0, Y: 50, Width: 50, Height: 50},
+		},
+	}
+
+	// Act: add rectangles, one of which is too large for the gap.
+	augmented, unplaced := binpack.AddToLayout(layout, []binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 60, Height: 60},
+	})
+
+	// Assert: the oversized rectangle should be unplaced.
+	require.Equal(t, []int{1}, unplaced)
+
+	// Assert: the existing placements should be unchanged and the new one should fill the gap.
+	require.Equal(t, 100, augmented.Width)
+	require.Equal(t, 100, augmented.Height)
+	require.Equal(t, []binpack.Placement{
+		{Index: 0, X: 0, Y: 0, Width: 100, Height: 50},
+		{Index: 1, X: 0, Y: 50, Width: 50, Height: 50},
+		{Index: 2, X: 50, Y: 50, Width: 50, Height: 50},
+	}, augmented.Placements)
+}
+
+// TestAddToLayout_Empty verifies that nothing can be added to an empty layout.
+func TestAddToLayout_Empty(t *testing.T) {
+	t.Parallel()
+
+	// Act: add rectangles to an empty layout.
+	augmented, unplaced := binpack.AddToLayout(binpack.Layout{}, []binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 20, Height: 20},
+	})
+
+	// Assert: every rectangle should be unplaced.
+	require.Equal(t, []int{0, 1}, unplaced)
+	require.Empty(t, augmented.Placements)
+}
+
+// TestLayout_At verifies that points are mapped back to the rectangle that
+// contains them.
+func TestLayout_At(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with two adjacent rectangles.
+	layout := binpack.Layout{
+		Width:  100,
+		Height: 60,
+		Placements: []binpack.Placement{
+			{Index: 3, X: 0, Y: 0, Width: 50, Height: 60},
+			{Index: 7, X: 50, Y: 0, Width: 50, Height: 30},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		x, y  int
+		index int
+		ok    bool
+	}{
+		{name: "TopLeftCorner", x: 0, y: 0, index: 3, ok: true},
+		{name: "SharedEdge", x: 50, y: 10, index: 7, ok: true},
+		{name: "Gap", x: 75, y: 45, ok: false},
+		{name: "Outside", x: 100, y: 0, ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: query the point.
+			index, ok := layout.At(tt.x, tt.y)
+
+			// Assert: the expected rectangle should be found.
+			require.Equal(t, tt.ok, ok)
+			if tt.ok {
+				require.Equal(t, tt.index, index)
+			}
+		})
+	}
+}
+
+// TestPlacement_Contains verifies that a placement contains those that lie
+// entirely within it, including along its edges.
+func TestPlacement_Contains(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create an outer placement.
+	outer := binpack.Placement{Index: 0, X: 10, Y: 10, Width: 50, Height: 40}
+
+	tests := []struct {
+		name     string
+		inner    binpack.Placement
+		contains bool
+	}{
+		{name: "Inside", inner: binpack.Placement{X: 20, Y: 20, Width: 10, Height: 10}, contains: true},
+		{name: "SharedEdges", inner: binpack.Placement{X: 10, Y: 10, Width: 50, Height: 40}, contains: true},
+		{name: "Crossing", inner: binpack.Placement{X: 50, Y: 20, Width: 20, Height: 10}, contains: false},
+		{name: "Outside", inner: binpack.Placement{X: 70, Y: 10, Width: 10, Height: 10}, contains: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act & Assert:
+			require.Equal(t, tt.contains, outer.Contains(tt.inner))
+		})
+	}
+
+	// Assert: points are tested like Layout.At.
+	require.True(t, outer.ContainsPoint(10, 10))
+	require.False(t, outer.ContainsPoint(60, 20))
+}
+
+// TestDiff verifies that only rectangles whose position changed are reported.
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create two layouts where two rectangles moved.
+	old := binpack.Layout{
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 10, Y: 0, Width: 10, Height: 10},
+			{Index: 2, X: 20, Y: 0, Width: 10, Height: 10},
+			{Index: 3, X: 30, Y: 0, Width: 10, Height: 10},
+		},
+	}
+	updated := binpack.Layout{
+		Placements: []binpack.Placement{
+			{Index: 2, X: 0, Y: 10, Width: 10, Height: 10},
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 20, Y: 0, Width: 10, Height: 10},
+			{Index: 4, X: 10, Y: 10, Width: 10, Height: 10},
+		},
+	}
+
+	// Act: diff the layouts.
+	changes := binpack.Diff(old, updated)
+
+	// Assert: the moved rectangles should be reported in index order.
+	require.Equal(t, []binpack.Change{
+		{Index: 1, OldX: 10, OldY: 0, NewX: 20, NewY: 0},
+		{Index: 2, OldX: 20, OldY: 0, NewX: 0, NewY: 10},
+	}, changes)
+}
+
+// TestLayout_FitInto verifies that layouts are scaled uniformly to fit within
+// a box, and only enlarged when asked.
+func TestLayout_FitInto(t *testing.T) {
+	t.Parallel()
+
+	layout := binpack.Layout{
+		Width:  200,
+		Height: 100,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 100, Height: 100},
+			{Index: 1, X: 100, Y: 0, Width: 100, Height: 100},
+		},
+	}
+	tests := []struct {
+		name         string
+		boxW, boxH   int
+		upscale      bool
+		wantW, wantH int
+		wantFactor   float64
+	}{
+		{name: "Shrink", boxW: 100, boxH: 100, wantW: 100, wantH: 50, wantFactor: 0.5},
+		{name: "NoUpscale", boxW: 400, boxH: 400, wantW: 200, wantH: 100, wantFactor: 1},
+		{name: "Upscale", boxW: 400, boxH: 400, upscale: true, wantW: 400, wantH: 200, wantFactor: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: fit the layout into the box.
+			fit := layout.FitInto
+			if tt.upscale {
+				fit = layout.FitIntoUpscaled
+			}
+			scaled, factor := fit(tt.boxW, tt.boxH)
+
+			// Assert: the layout should be scaled by the expected factor.
+			require.Equal(t, tt.wantFactor, factor)
+			require.Equal(t, tt.wantW, scaled.Width)
+			require.Equal(t, tt.wantH, scaled.Height)
+			require.Equal(t, tt.wantW/2, scaled.Placements[1].X)
+		})
+	}
+}
+
+// TestLayout_FitIntoRounding verifies that the scaled layout never exceeds the
+// box, even when rounding would push it past.
+func TestLayout_FitIntoRounding(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a row of narrow rectangles that round up when scaled.
+	layout := binpack.Layout{Width: 9, Height: 3}
+	for i := 0; i < 3; i++ {
+		layout.Placements = append(layout.Placements, binpack.Placement{Index: i, X: 3 * i, Width: 3, Height: 3})
+	}
+
+	// Act: fit the layout into a box that needs a scale of at most 4/9.
+	scaled, factor := layout.FitInto(4, 4)
+
+	// Assert: the layout should fit within the box without overlaps.
+	require.LessOrEqual(t, factor, 4.0/9)
+	require.LessOrEqual(t, scaled.Width, 4)
+	require.LessOrEqual(t, scaled.Height, 4)
+	requireNoOverlap(t, scaled)
+}
+
+// TestOccupancyGrid verifies that cells are marked when covered, either in
+// part or in full.
+func TestOccupancyGrid(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a layout with a placement that ends partway through a cell.
+	layout := binpack.Layout{
+		Width:  30,
+		Height: 20,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 15, Height: 10},
+			{Index: 1, X: 20, Y: 10, Width: 10, Height: 10},
+		},
+	}
+
+	// Act: compute both grids with 10 pixel cells.
+	partial := binpack.OccupancyGrid(layout, 10)
+	strict := binpack.OccupancyGridStrict(layout, 10)
+
+	// Assert: the partly covered cell should only be marked by the default rule.
+	require.Equal(t, [][]bool{
+		{true, true, false},
+		{false, false, true},
+	}, partial)
+	require.Equal(t, [][]bool{
+		{true, false, false},
+		{false, false, true},
+	}, strict)
+	require.Nil(t, binpack.OccupancyGrid(layout, 0))
+}
+
+// TestAdjacencyGraph verifies that rectangles sharing an edge are neighbours
+// and those meeting only at a corner are not.
+func TestAdjacencyGraph(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: place a tall rectangle beside two stacked ones, and another touching only a corner.
+	layout := binpack.Layout{
+		Width:  40,
+		Height: 30,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 20},
+			{Index: 1, X: 10, Y: 0, Width: 10, Height: 10},
+			{Index: 2, X: 10, Y: 10, Width: 10, Height: 10},
+			{Index: 3, X: 20, Y: 20, Width: 10, Height: 10},
+			{Index: 4, X: 0, Y: 0, Width: 0, Height: 0},
+		},
+	}
+
+	// Act: build the adjacency graph.
+	graph := binpack.AdjacencyGraph(layout)
+
+	// Assert: only the touching rectangles are neighbours, and the empty one is left out.
+	require.Equal(t, map[int][]int{
+		0: {1, 2},
+		1: {0, 2},
+		2: {0, 1},
+		3: {},
+	}, graph)
+}
+
+// TestLayout_Rotate90 verifies that placements are rotated clockwise with
+// their dimensions swapped.
+func TestLayout_Rotate90(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: place a wide rectangle above a square one.
+	layout := binpack.Layout{
+		Width:  30,
+		Height: 20,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 30, Height: 10},
+			{Index: 1, X: 20, Y: 10, Width: 10, Height: 10},
+		},
+	}
+
+	// Act: rotate the layout a quarter turn.
+	rotated := layout.Rotate90()
+
+	// Assert: the top row becomes the right column.
+	require.Equal(t, 20, rotated.Width)
+	require.Equal(t, 30, rotated.Height)
+	require.Equal(t, []binpack.Placement{
+		{Index: 0, X: 10, Y: 0, Width: 10, Height: 30},
+		{Index: 1, X: 0, Y: 20, Width: 10, Height: 10},
+	}, rotated.Placements)
+	require.Equal(t, 30, layout.Width, "the original layout is unchanged")
+}
+
+// TestCombine verifies that layouts are arranged with a gap between them and
+// their placements renumbered.
+func TestCombine(t *testing.T) {
+	t.Parallel()
+
+	first := binpack.Layout{
+		Width:  20,
+		Height: 10,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 10, Y: 0, Width: 10, Height: 10},
+		},
+	}
+	second := binpack.Layout{
+		Width:      30,
+		Height:     30,
+		Placements: []binpack.Placement{{Index: 0, X: 0, Y: 0, Width: 30, Height: 30}},
+	}
+	tests := []struct {
+		name          string
+		direction     binpack.Direction
+		width, height int
+		x, y          int
+	}{
+		{name: "Horizontal", direction: binpack.DirectionHorizontal, width: 55, height: 30, x: 25, y: 0},
+		{name: "Vertical", direction: binpack.DirectionVertical, width: 30, height: 45, x: 0, y: 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: combine the layouts with an empty one between them.
+			combined := binpack.Combine([]binpack.Layout{first, {}, second}, 5, tt.direction)
+
+			// Assert: the second layout should follow the first after the gap.
+			require.Equal(t, tt.width, combined.Width)
+			require.Equal(t, tt.height, combined.Height)
+			require.Equal(t, first.Placements, combined.Placements[:2])
+			require.Equal(t, binpack.Placement{Index: 2, X: tt.x, Y: tt.y, Width: 30, Height: 30}, combined.Placements[2])
+		})
+	}
+}
+
+// TestCombine_Cells verifies that the cells of grid layouts are moved with
+// their placements.
+func TestCombine_Cells(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: a grid layout with two cells, followed by one without cells.
+	grid := binpack.Layout{
+		Width:  40,
+		Height: 20,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 5, Y: 5, Width: 10, Height: 10},
+			{Index: 1, X: 20, Y: 0, Width: 20, Height: 20},
+		},
+		Cells: []image.Rectangle{image.Rect(0, 0, 20, 20), image.Rect(20, 0, 40, 20)},
+	}
+	plain := binpack.Layout{
+		Width:      10,
+		Height:     10,
+		Placements: []binpack.Placement{{Index: 0, X: 0, Y: 0, Width: 10, Height: 10}},
+	}
+
+	// Act: stack the plain layout above the grid.
+	combined := binpack.Combine([]binpack.Layout{plain, grid}, 5, binpack.DirectionVertical)
+
+	// Assert: the grid cells move down with their placements, and the plain placement is its own cell.
+	require.Equal(t, []image.Rectangle{
+		image.Rect(0, 0, 10, 10),
+		image.Rect(0, 15, 20, 35),
+		image.Rect(20, 15, 40, 35),
+	}, combined.Cells)
+	require.Equal(t, binpack.Placement{Index: 1, X: 5, Y: 20, Width: 10, Height: 10}, combined.Placements[1])
+}
+
+// TestResolveOverlaps verifies that only the placements overlapping the moved
+// one are repositioned, each to the nearest free position.
+func TestResolveOverlaps(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: drag rectangle 0 on top of rectangle 1, leaving 2 alone.
+	layout := binpack.Layout{
+		Width:  60,
+		Height: 20,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 15, Y: 0, Width: 20, Height: 20},
+			{Index: 1, X: 20, Y: 0, Width: 20, Height: 20},
+			{Index: 2, X: 40, Y: 0, Width: 20, Height: 20},
+		},
+	}
+
+	// Act: resolve the overlaps, keeping rectangle 0 in place.
+	resolved := binpack.ResolveOverlaps(layout, 0)
+
+	// Assert: rectangle 1 should move straight down, the nearest free position.
+	require.Equal(t, []binpack.Placement{
+		{Index: 0, X: 15, Y: 0, Width: 20, Height: 20},
+		{Index: 1, X: 20, Y: 20, Width: 20, Height: 20},
+		{Index: 2, X: 40, Y: 0, Width: 20, Height: 20},
+	}, resolved.Placements)
+	require.Equal(t, 60, resolved.Width)
+	require.Equal(t, 40, resolved.Height)
+	require.Equal(t, 20, layout.Placements[1].X, "the input layout should not change")
+	requireNoOverlap(t, resolved)
+}
@@ -0,0 +1,42 @@
+package binpack
+
+// PackMasonry arranges sizes into a masonry gallery of columns columns, each
+// columnWidth wide. Every rectangle is scaled to the width of a column,
+// keeping its aspect ratio with the height rounded to the nearest pixel, and
+// stacked at the bottom of the shortest column, the leftmost if several are
+// equally short. The placements hold the scaled sizes in index order. The
+// layout is as wide as all the columns and as tall as the tallest.
+// Rectangles with no area are placed at (0, 0) unscaled. Returns an empty
+// layout if columnWidth or columns is not positive.
+func PackMasonry(sizes []Rectangle, columnWidth, columns int) Layout {
+	if columnWidth <= 0 || columns <= 0 {
+		return Layout{}
+	}
+
+	var layout = Layout{Width: columnWidth * columns, Placements: make([]Placement, 0, len(sizes))}
+	var heights = make([]int, columns)
+	for i, r := range sizes {
+		if r.area64() == 0 {
+			layout.Placements = append(layout.Placements, Placement{Index: i, Width: r.Width, Height: r.Height})
+			continue
+		}
+
+		var shortest = 0
+		for column, height := range heights {
+			if height < heights[shortest] {
+				shortest = column
+			}
+		}
+		var height = int((int64(r.Height)*int64(columnWidth) + int64(r.Width)/2) / int64(r.Width))
+		layout.Placements = append(layout.Placements, Placement{
+			Index:  i,
+			X:      shortest * columnWidth,
+			Y:      heights[shortest],
+			Width:  columnWidth,
+			Height: height,
+		})
+		heights[shortest] += height
+		layout.Height = max(layout.Height, heights[shortest])
+	}
+	return layout
+}
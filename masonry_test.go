@@ -0,0 +1,38 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackMasonry verifies that rectangles are scaled to the column width and
+// stacked into the shortest column.
+func TestPackMasonry(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: a tall image, a wide image, a square and a placeholder.
+	sizes := []binpack.Rectangle{
+		{Width: 50, Height: 150},
+		{Width: 200, Height: 100},
+		{Width: 30, Height: 30},
+		{Width: 0, Height: 10},
+		{Width: 300, Height: 200},
+	}
+
+	// Act: lay the images out in two columns 100 pixels wide.
+	layout := binpack.PackMasonry(sizes, 100, 2)
+
+	// Assert: each image is scaled to the column width and added to the shortest column.
+	require.Equal(t, []binpack.Placement{
+		{Index: 0, X: 0, Y: 0, Width: 100, Height: 300},
+		{Index: 1, X: 100, Y: 0, Width: 100, Height: 50},
+		{Index: 2, X: 100, Y: 50, Width: 100, Height: 100},
+		{Index: 3, X: 0, Y: 0, Width: 0, Height: 10},
+		{Index: 4, X: 100, Y: 150, Width: 100, Height: 67},
+	}, layout.Placements)
+	require.Equal(t, 200, layout.Width)
+	require.Equal(t, 300, layout.Height)
+	require.Empty(t, binpack.PackMasonry(sizes, 100, 0).Placements)
+}
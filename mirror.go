@@ -0,0 +1,151 @@
+package binpack
+
+import "sort"
+
+// Mirrorer is an optional interface for Packables with rectangles in mirrored
+// pairs, such as the before and after images of a comparison. The first of
+// each pair is packed into the left half of the layout, and the other is
+// placed at the reflection of its position across the vertical centerline,
+// so the layout is twice as wide as the left half and mirror-symmetric.
+// Rectangles without a pair are packed into the left half too. The pair
+// shares a slot as large as the larger of the two in each dimension, so
+// rectangles of different sizes never overlap their reflection.
+type Mirrorer interface {
+	// MirrorPair returns the index of the rectangle mirrored with the
+	// rectangle at index n, or false if it has none.
+	MirrorPair(n int) (other int, ok bool)
+}
+
+// collectMirrorPairs returns the other rectangle of each pair in p, keyed by
+// the lower index of the pair, which is the one packed. Pairs with gaps or
+// with a rectangle already in another pair are ignored.
+func collectMirrorPairs(p Packable) map[int]int {
+	var m, ok = optional[Mirrorer](p)
+	if !ok {
+		return nil
+	}
+	var skip = skipper(p)
+	var pairs = make(map[int]int)
+	var paired = make(map[int]bool)
+	for i := 0; i < p.Len(); i++ {
+		var other, ok = m.MirrorPair(i)
+		if !ok || other == i || other < 0 || other >= p.Len() || skip(i) || skip(other) || paired[i] || paired[other] {
+			continue
+		}
+		pairs[min(i, other)] = max(i, other)
+		paired[i], paired[other] = true, true
+	}
+	return pairs
+}
+
+// packMirrored computes the layout for p by packing the first of each pair
+// into the left half of the layout, within half of MaxWidth, and reflecting
+// it to place the other. The other rectangle of a pair shares the fate of
+// the first when it cannot be placed.
+func packMirrored(p Packable, opts Options, pairs map[int]int) (Layout, []int, []int) {
+	var half = opts
+	half.MaxWidth /= 2
+	// Reflect about the half as packed, and translate the whole layout after.
+	half.Relative = false
+	if opts.MaxWidth > 0 && half.MaxWidth == 0 {
+		return Layout{}, allIndices(p), nil
+	}
+	var m = &mirrored{forwarded: forwarded{p}, pairs: pairs, second: make(map[int]bool, len(pairs))}
+	for _, second := range pairs {
+		m.second[second] = true
+	}
+	var layout, unplaced, dropped = pack(m, half)
+
+	// Reflect each placed pair across the right edge of the left half.
+	var width = layout.Width
+	layout.Width *= 2
+	for i, placed := range layout.Placements {
+		var second, ok = pairs[placed.Index]
+		if !ok {
+			continue
+		}
+		var first, other = p.Rectangle(placed.Index), p.Rectangle(second)
+		layout.Placements[i].Width, layout.Placements[i].Height = first.Width, first.Height
+		var reflected = Placement{Index: second, Y: placed.Y, Width: other.Width, Height: other.Height}
+		if other.area64() > 0 {
+			reflected.X = 2*width - placed.X - other.Width
+		}
+		layout.Placements = append(layout.Placements, reflected)
+		if layout.Cells != nil {
+			var cell = layout.Cells[i]
+			cell.Min.X, cell.Max.X = 2*width-cell.Max.X, 2*width-cell.Min.X
+			layout.Cells = append(layout.Cells, cell)
+		}
+	}
+	if opts.OrderBy == OrderInput {
+		sort.Sort(byIndex(layout))
+	}
+
+	// Leave out the other rectangle of the pairs that were not placed.
+	var seconds = func(indices []int) []int {
+		for _, n := range indices {
+			if second, ok := pairs[n]; ok {
+				indices = append(indices, second)
+			}
+		}
+		sort.Ints(indices)
+		return indices
+	}
+	layout.excluded = seconds(layout.excluded)
+	return layout, seconds(unplaced), seconds(dropped)
+}
+
+// allIndices returns the indices of the rectangles in p that are not gaps.
+func allIndices(p Packable) []int {
+	var skip = skipper(p)
+	var indices []int
+	for i := 0; i < p.Len(); i++ {
+		if !skip(i) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// mirrored hides the second rectangle of each mirrored pair of a Packable,
+// and sizes the first to hold either of them.
+type mirrored struct {
+	forwarded
+	pairs  map[int]int
+	second map[int]bool
+}
+
+// Rectangle returns the slot shared by a pair, or the rectangle itself if it
+// has no pair.
+func (m *mirrored) Rectangle(n int) Rectangle {
+	var r = m.Packable.Rectangle(n)
+	if second, ok := m.pairs[n]; ok {
+		var other = m.Packable.Rectangle(second)
+		r = Rectangle{Width: max(r.Width, other.Width), Height: max(r.Height, other.Height)}
+	}
+	return r
+}
+
+// Skip returns true for the gaps of the wrapped Packable and for the second
+// rectangle of each pair.
+func (m *mirrored) Skip(n int) bool {
+	return m.second[n] || m.forwarded.Skip(n)
+}
+
+// MirrorPair hides the pairs, which have already been collected.
+func (m *mirrored) MirrorPair(int) (int, bool) {
+	return 0, false
+}
+
+// byIndex sorts the placements of a layout by index, keeping their cells in
+// step.
+type byIndex Layout
+
+func (l byIndex) Len() int           { return len(l.Placements) }
+func (l byIndex) Less(i, j int) bool { return l.Placements[i].Index < l.Placements[j].Index }
+func (l byIndex) Swap(i, j int) {
+	l.Placements[i], l.Placements[j] = l.Placements[j], l.Placements[i]
+	if l.Cells != nil {
+		l.Cells[i], l.Cells[j] = l.Cells[j], l.Cells[i]
+	}
+}
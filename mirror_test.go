@@ -0,0 +1,66 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testMirrorPackable extends testPackable with mirrored pairs.
+type testMirrorPackable struct {
+	*testPackable
+	pairs map[int]int
+}
+
+// Ensure that testMirrorPackable implements the binpack.Mirrorer interface.
+var _ binpack.Mirrorer = (*testMirrorPackable)(nil)
+
+// MirrorPair returns the rectangle mirrored with the one at the specified index.
+func (tp *testMirrorPackable) MirrorPair(n int) (int, bool) {
+	other, ok := tp.pairs[n]
+	return other, ok
+}
+
+// TestMirrorer verifies that the second rectangle of each pair is placed at
+// the reflection of the first across the vertical centerline.
+func TestMirrorer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+	}{
+		{name: "Default", opts: binpack.Options{}},
+		{name: "Grid", opts: binpack.Options{Heuristic: binpack.HeuristicGrid, Columns: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: pair 0 with 3, and 1 with 2, which is narrower.
+			tp := &testMirrorPackable{
+				testPackable: newTestPackable([]binpack.Rectangle{
+					{Width: 40, Height: 30},
+					{Width: 20, Height: 20},
+					{Width: 10, Height: 20},
+					{Width: 40, Height: 30},
+				}),
+				pairs: map[int]int{0: 3, 3: 0, 2: 1},
+			}
+
+			// Act: pack the mirrored pairs.
+			layout, err := binpack.PackWithOptions(tp, tt.opts)
+
+			// Assert: each pair should mirror the other's right edge.
+			require.NoError(t, err)
+			require.Len(t, layout.Placements, 4)
+			require.Equal(t, layout.Width-tp.placements[0].x, tp.placements[3].x+40)
+			require.Equal(t, tp.placements[0].y, tp.placements[3].y)
+			require.Equal(t, layout.Width-tp.placements[1].x, tp.placements[2].x+10)
+			require.Equal(t, tp.placements[1].y, tp.placements[2].y)
+			require.Less(t, tp.placements[0].x+40, layout.Width/2+1)
+			requireNoOverlap(t, layout)
+		})
+	}
+}
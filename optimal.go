@@ -0,0 +1,224 @@
+package binpack
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	// maxOptimalRectangles is the largest number of rectangles PackOptimal
+	// will search.
+	maxOptimalRectangles = 8
+
+	// maxOptimalNodes is the number of partial layouts PackOptimal will
+	// visit before giving up.
+	maxOptimalNodes = 2_000_000
+
+	// optimalDeadlineInterval is the number of partial layouts visited
+	// between checks of the deadline.
+	optimalDeadlineInterval = 1024
+)
+
+// optimalSearch holds the state of the exhaustive search run by PackOptimal.
+type optimalSearch struct {
+	rects      []Rectangle
+	positions  []int
+	binW, binH int
+	totalArea  int64
+	maxW, maxH int
+	nodes      int
+	used       []bool
+	placements []placement
+	best       []placement
+	bestArea   int64
+	bestSize   int
+	deadline   time.Time
+	expired    bool
+}
+
+// PackOptimal finds the packing of p with the smallest bounding box area that
+// fits within binW x binH, places the rectangles, and returns the layout.
+// A non-positive dimension is unlimited. The search is exhaustive, so it is
+// only attempted for up to 8 rectangles and gives up after visiting a fixed
+// number of partial layouts; in either case, or if the rectangles do not fit,
+// nothing is placed and false is returned so the caller can fall back to
+// PackWithOptions. Exclusive groups and alignments are ignored, and skipped rectangles are
+// left out.
+func PackOptimal(p Packable, binW, binH int) (Layout, bool) {
+	var layout, ok = packOptimal(p, binW, binH, time.Time{})
+	if ok {
+		layout.apply(p)
+	}
+	return layout, ok
+}
+
+// packOptimal computes the layout for PackOptimal without placing the
+// rectangles. If the deadline is set and passes, the search stops and the
+// best layout found so far is returned.
+func packOptimal(p Packable, binW, binH int, deadline time.Time) (Layout, bool) {
+	var s = &optimalSearch{binW: binW, binH: binH, deadline: deadline}
+	var skip = skipper(p)
+	s.rects = make([]Rectangle, p.Len())
+	for i := range s.rects {
+		s.rects[i] = p.Rectangle(i)
+		if s.rects[i].Area() > 0 && !skip(i) {
+			s.positions = append(s.positions, i)
+			s.totalArea += s.rects[i].area64()
+			s.maxW = max(s.maxW, s.rects[i].Width)
+			s.maxH = max(s.maxH, s.rects[i].Height)
+		}
+	}
+	if len(s.positions) > maxOptimalRectangles {
+		return Layout{}, false
+	}
+
+	// Seed the search with the heuristic layout so that only strictly better layouts are explored.
+	s.bestArea = -1
+	if layout, unplaced, _ := pack(&packableSnapshot{rectangleSlice: s.rects, skipped: s.skipped()}, Options{MaxWidth: max(binW, 0), MaxHeight: max(binH, 0)}); len(unplaced) == 0 {
+		for _, placement := range layout.Placements {
+			if s.rects[placement.Index].Area() > 0 {
+				s.best = append(s.best, newPlacement(placement))
+			}
+		}
+		s.bestArea = int64(layout.Width) * int64(layout.Height)
+		s.bestSize = layout.Width + layout.Height
+	}
+
+	s.used = make([]bool, len(s.positions))
+	if (!s.search(0, 0) && !s.expired) || s.bestArea < 0 {
+		return Layout{}, false
+	}
+
+	var layout Layout
+	for _, placement := range s.best {
+		layout.Width = max(layout.Width, placement.x+placement.width)
+		layout.Height = max(layout.Height, placement.y+placement.height)
+	}
+	for i, r := range s.rects {
+		if r.Area() > 0 || skip(i) {
+			continue
+		}
+		s.best = append(s.best, placement{position: i, width: r.Width, height: r.Height})
+	}
+	sort.Slice(s.best, func(i, j int) bool {
+		return s.best[i].position < s.best[j].position
+	})
+	for _, placement := range s.best {
+		layout.Placements = append(layout.Placements, Placement{
+			Index:  placement.position,
+			X:      placement.x,
+			Y:      placement.y,
+			Width:  placement.width,
+			Height: placement.height,
+		})
+	}
+	return layout, true
+}
+
+// skipped returns the indices of the rectangles left out of the search.
+func (s *optimalSearch) skipped() map[int]bool {
+	var skipped = make(map[int]bool)
+	for i := range s.rects {
+		skipped[i] = true
+	}
+	for _, position := range s.positions {
+		delete(skipped, position)
+	}
+	return skipped
+}
+
+// newPlacement converts a public placement back into its internal form.
+func newPlacement(p Placement) placement {
+	return placement{position: p.Index, x: p.X, y: p.Y, width: p.Width, height: p.Height}
+}
+
+// search extends the current partial layout, whose bounding box is width x
+// height, with every remaining rectangle at every candidate position. It
+// returns false if the node limit was reached or the deadline passed.
+func (s *optimalSearch) search(width, height int) bool {
+	if len(s.placements) == len(s.positions) {
+		var area = int64(width) * int64(height)
+		if s.bestArea < 0 || area < s.bestArea || (area == s.bestArea && width+height < s.bestSize) {
+			s.best = append(s.best[:0], s.placements...)
+			s.bestArea, s.bestSize = area, width+height
+		}
+		return true
+	}
+
+	// Every rectangle can be pushed left and down until it touches the origin or another rectangle,
+	// so it suffices to try the origin and the far edges of the rectangles already placed.
+	var xs, ys = []int{0}, []int{0}
+	for _, placement := range s.placements {
+		xs = append(xs, placement.x+placement.width)
+		ys = append(ys, placement.y+placement.height)
+	}
+
+	for i, position := range s.positions {
+		var r = s.rects[position]
+		if s.used[i] || s.duplicate(i) {
+			continue
+		}
+		s.used[i] = true
+		for _, x := range xs {
+			for _, y := range ys {
+				var candidate = placement{position: position, x: x, y: y, width: r.Width, height: r.Height}
+				var w, h = max(width, x+r.Width), max(height, y+r.Height)
+				if (s.binW > 0 && w > s.binW) || (s.binH > 0 && h > s.binH) || !s.improves(w, h) || !s.supported(candidate) || hasIntersection(candidate, s.placements, nil) {
+					continue
+				}
+				if s.nodes++; s.nodes > maxOptimalNodes {
+					return false
+				}
+				if s.nodes%optimalDeadlineInterval == 0 && !s.deadline.IsZero() && time.Now().After(s.deadline) {
+					s.expired = true
+					return false
+				}
+				s.placements = append(s.placements, candidate)
+				var ok = s.search(w, h)
+				s.placements = s.placements[:len(s.placements)-1]
+				if !ok {
+					return false
+				}
+			}
+		}
+		s.used[i] = false
+	}
+	return true
+}
+
+// duplicate reports whether an earlier unused rectangle has the same size as
+// the rectangle at s.positions[i], in which case trying it again is redundant.
+func (s *optimalSearch) duplicate(i int) bool {
+	for j := 0; j < i; j++ {
+		if !s.used[j] && s.rects[s.positions[j]] == s.rects[s.positions[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// improves reports whether a partial layout with a width x height bounding
+// box could still lead to a layout better than the best found so far.
+func (s *optimalSearch) improves(width, height int) bool {
+	if s.bestArea < 0 {
+		return true
+	}
+	var area = max(int64(max(width, s.maxW))*int64(max(height, s.maxH)), s.totalArea)
+	return area < s.bestArea || (area == s.bestArea && max(width, s.maxW)+max(height, s.maxH) < s.bestSize)
+}
+
+// supported reports whether the candidate cannot be pushed further left or
+// down, because it touches either the origin axes or a placed rectangle on
+// each of those sides.
+func (s *optimalSearch) supported(candidate placement) bool {
+	var left, below = candidate.x == 0, candidate.y == 0
+	for _, p := range s.placements {
+		if !left && p.x+p.width == candidate.x && p.y < candidate.y+candidate.height && candidate.y < p.y+p.height {
+			left = true
+		}
+		if !below && p.y+p.height == candidate.y && p.x < candidate.x+candidate.width && candidate.x < p.x+p.width {
+			below = true
+		}
+	}
+	return left && below
+}
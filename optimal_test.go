@@ -0,0 +1,92 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackOptimal_PerfectTiling verifies that rectangles which tile a square
+// exactly are packed without any wasted space.
+func TestPackOptimal_PerfectTiling(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles that tile a 100x100 square.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 40, Height: 60},
+		{Width: 60, Height: 40},
+		{Width: 40, Height: 40},
+		{Width: 60, Height: 60},
+	})
+
+	// Act: pack the rectangles optimally.
+	layout, ok := binpack.PackOptimal(tp, 0, 0)
+
+	// Assert: the layout should be the square with every rectangle placed.
+	require.True(t, ok)
+	require.Equal(t, 100, layout.Width)
+	require.Equal(t, 100, layout.Height)
+	require.Len(t, layout.Placements, 4)
+	requireNoOverlap(t, layout)
+	for _, placement := range layout.Placements {
+		require.Equal(t, placement.X, tp.placements[placement.Index].x)
+		require.Equal(t, placement.Y, tp.placements[placement.Index].y)
+	}
+}
+
+// TestPackOptimal_Bin verifies that the layout respects the bin dimensions.
+func TestPackOptimal_Bin(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles that only fit the bin when stacked.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 50, Height: 30},
+		{Width: 50, Height: 30},
+		{Width: 50, Height: 30},
+	})
+
+	// Act: pack the rectangles into a narrow bin.
+	layout, ok := binpack.PackOptimal(tp, 60, 100)
+
+	// Assert: the rectangles should be stacked vertically.
+	require.True(t, ok)
+	require.Equal(t, 50, layout.Width)
+	require.Equal(t, 90, layout.Height)
+	requireNoOverlap(t, layout)
+}
+
+// TestPackOptimal_Refuses verifies that PackOptimal refuses inputs it cannot
+// solve and leaves the rectangles unplaced.
+func TestPackOptimal_Refuses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		rectangles []binpack.Rectangle
+		binW, binH int
+	}{
+		{name: "too many rectangles", rectangles: randomRectangles(9)},
+		{name: "does not fit", rectangles: []binpack.Rectangle{{Width: 50, Height: 50}, {Width: 50, Height: 50}}, binW: 60, binH: 60},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create the test packable.
+			tp := newTestPackable(test.rectangles)
+
+			// Act: attempt to pack the rectangles optimally.
+			layout, ok := binpack.PackOptimal(tp, test.binW, test.binH)
+
+			// Assert: nothing should be packed or placed.
+			require.False(t, ok)
+			require.Empty(t, layout.Placements)
+			for _, placement := range tp.placements {
+				require.Zero(t, placement.x)
+				require.Zero(t, placement.y)
+			}
+		})
+	}
+}
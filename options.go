@@ -0,0 +1,559 @@
+package binpack
+
+import (
+	"image"
+	"math"
+	"slices"
+	"time"
+)
+
+// Score selects the metric minimized when choosing a candidate position.
+type Score int
+
+const (
+	// ScoreArea minimizes the area of the bounding box. This is the default.
+	ScoreArea Score = iota
+	// ScorePerimeter minimizes the perimeter of the bounding box, favoring
+	// square layouts over long thin ones.
+	ScorePerimeter
+	// ScoreWeightedCenter minimizes the area of the bounding box plus a
+	// penalty for the distance between the rectangle and the center of the
+	// bounding box, scaled by the rectangle's share of the area. Large
+	// rectangles are pulled toward the center while small ones may settle at
+	// the edges.
+	ScoreWeightedCenter
+	// ScoreMaxSide minimizes the larger of the width and height of the
+	// bounding box, then its area, suiting textures that are allocated as a
+	// bounding square.
+	ScoreMaxSide
+)
+
+// evaluate returns the score of the bounding box b that results from placing
+// candidate, as a primary value followed by a value that breaks ties between
+// equal primaries. Lower is better.
+func (s Score) evaluate(candidate placement, b bounds) [2]int64 {
+	var width, height = int64(b.maxX - b.minX), int64(b.maxY - b.minY)
+	switch s {
+	case ScorePerimeter:
+		return [2]int64{2 * (width + height)}
+	case ScoreWeightedCenter:
+		var area = width * height
+		if area == 0 {
+			return [2]int64{}
+		}
+		// Compute the penalty in floating point, as the product can exceed 64 bits.
+		var share = float64(candidate.area()) / float64(area)
+		return [2]int64{area + int64(share*float64(centerDistance(candidate, b.center())))}
+	case ScoreMaxSide:
+		return [2]int64{max(width, height), width * height}
+	default:
+		return [2]int64{width * height}
+	}
+}
+
+// weigh returns the score of the bounding box b that results from placing
+// candidate, as set by AreaWeight and DistanceWeight. The sum is computed in
+// floating point and clamped to 64 bits.
+func (o Options) weigh(candidate placement, b bounds) int64 {
+	var area = float64(b.maxX-b.minX) * float64(b.maxY-b.minY)
+	var sum = o.AreaWeight*area + o.DistanceWeight*float64(centerDistance(candidate, b.center()))
+	return int64(max(min(math.Round(sum), math.MaxInt64/2), math.MinInt64/2))
+}
+
+// biasedArea returns the area of the bounding box b with the width and
+// height raised to the powers set by WidthBias, followed by the plain area to
+// break ties. The powers are computed in floating point and clamped to 64
+// bits.
+func (o Options) biasedArea(b bounds) [2]int64 {
+	var bias = max(min(o.WidthBias, 1), -1)
+	var width, height = float64(b.maxX - b.minX), float64(b.maxY - b.minY)
+	var area = math.Pow(width, 1-bias) * math.Pow(height, 1+bias)
+	return [2]int64{int64(min(math.Round(area), math.MaxInt64/2)), int64(b.maxX-b.minX) * int64(b.maxY-b.minY)}
+}
+
+// TieBreak selects how candidate positions with equal scores are resolved.
+// Coordinates grow right and down from the top-left corner of the layout.
+type TieBreak int
+
+const (
+	// TieBreakCenter favors the candidate closest to the center of the
+	// bounding box. This is the default.
+	TieBreakCenter TieBreak = iota
+	// TieBreakBottomLeft favors the candidate whose bottom edge is lowest,
+	// then the leftmost.
+	TieBreakBottomLeft
+	// TieBreakTopLeft favors the candidate whose top edge is highest, then
+	// the leftmost.
+	TieBreakTopLeft
+	// TieBreakLargestFree favors the candidate that leaves the largest empty
+	// rectangle within the bounding box, keeping room for the rectangles
+	// placed later, then the one closest to the center. Finding the empty
+	// rectangle takes time in proportion to the square of the number of
+	// placements, for every candidate that ties with the best so far.
+	TieBreakLargestFree
+)
+
+// GridRounding selects how positions snap to Options.Grid.
+type GridRounding int
+
+const (
+	// RoundUp snaps positions to the next multiple of the grid. This is the
+	// default.
+	RoundUp GridRounding = iota
+	// RoundDown snaps positions to the previous multiple of the grid.
+	RoundDown
+	// RoundNearest snaps positions to the nearest multiple of the grid,
+	// rounding halfway positions up.
+	RoundNearest
+)
+
+// Order selects the order of the placements in a Layout.
+type Order int
+
+const (
+	// OrderInput lists the placements by ascending index, so they can be
+	// correlated with the rectangles. This is the default.
+	OrderInput Order = iota
+	// OrderPlacement lists the placements in the order they were placed,
+	// such as for animating the layout being built. Rectangles with no area
+	// are listed last.
+	OrderPlacement
+)
+
+// Heuristic selects the algorithm used to arrange rectangles.
+type Heuristic int
+
+const (
+	// HeuristicDefault packs rectangles as compactly as possible, placing the
+	// largest first. This is the default.
+	HeuristicDefault Heuristic = iota
+	// HeuristicGrid places rectangle i into the cell at column i % Columns and
+	// row i / Columns of a uniform grid, in index order. Rectangles larger
+	// than a cell are scaled down with Rectangle.Scale to fit it, keeping
+	// their proportions; their placements have the scaled dimensions, and a
+	// Flexible is told them through Resize.
+	HeuristicGrid
+	// HeuristicAuto chooses the algorithm by the number of rectangles with
+	// area. Up to 6 are packed by the exhaustive search of PackOptimal,
+	// which finds the smallest layout in milliseconds at that size. From
+	// 500, where the default heuristic takes over a second, they are placed
+	// on shelves, tallest first, trading some space for n log n time.
+	// Between the two, and whenever options other than MaxWidth and
+	// MaxHeight or optional interfaces such as Pinner are in use, the
+	// default heuristic is used.
+	HeuristicAuto
+	// HeuristicFilmstrip places rectangles of the same height, such as the
+	// frames of an animation, in index order from left to right, wrapping
+	// to a new row at MaxWidth, so the order of the frames is kept.
+	// PackWithOptions returns ErrUnevenHeight if the heights differ, and
+	// PackBestEffort leaves the rectangles that differ from the first
+	// unplaced.
+	HeuristicFilmstrip
+)
+
+// Options configures how rectangles are packed.
+type Options struct {
+	// MaxWidth limits the width of the layout. Zero means unbounded.
+	MaxWidth int
+	// MaxHeight limits the height of the layout. Zero means unbounded.
+	MaxHeight int
+	// Score selects the metric minimized when choosing candidate positions.
+	Score Score
+	// TieBreak selects how candidates with equal scores are resolved.
+	TieBreak TieBreak
+	// AreaWeight and DistanceWeight replace Score with the weighted sum of
+	// the area of the bounding box and the squared distance between the
+	// rectangle and its center, so both terms are in square units. Raising
+	// DistanceWeight trades a larger layout for rectangles that gather
+	// around the center; AreaWeight 1 and DistanceWeight 0 match ScoreArea.
+	// The further the ratio leans one way, the more that term dominates, so
+	// the layout moves predictably from the most compact arrangement toward
+	// the most centered one. Both zero means Score is used.
+	AreaWeight, DistanceWeight float64
+	// WidthBias nudges the proportions of the layout with ScoreArea, from -1
+	// to 1, by scaling the contribution of the width to the area of the
+	// bounding box: the area is scored as width^(1-WidthBias) x
+	// height^(1+WidthBias). A positive bias favors wider layouts and a
+	// negative one taller layouts, with 1 and -1 only minimizing the height
+	// and width respectively. Unlike MinAspect and MaxAspect, no proportions
+	// are enforced. Zero is neutral, and values outside the range are
+	// clamped.
+	WidthBias float64
+	// Limit is the maximum number of rectangles to pack. The first
+	// rectangles in placement order, which are the largest unless LessIndex
+	// is set, are kept and the rest are left unplaced; PackBestEffort
+	// reports them alongside the rectangles that did not fit, while
+	// PackWithOptions does not treat them as an error. Rectangles in an
+	// exclusive group count once. Zero means no limit.
+	Limit int
+	// MinAspect and MaxAspect constrain the ratio of width to height of the
+	// layout. Candidates that keep the layout within the range are always
+	// preferred, and those outside it are ranked by how far they stray.
+	// Zero means no limit.
+	MinAspect, MaxAspect float64
+	// Heuristic selects the algorithm used to arrange rectangles.
+	Heuristic Heuristic
+	// Columns is the number of columns used by HeuristicGrid. Zero chooses
+	// enough columns to make the grid roughly square.
+	Columns int
+	// CellWidth and CellHeight are the dimensions of each cell used by
+	// HeuristicGrid. Zero sizes the cells to the widest and tallest
+	// rectangles.
+	CellWidth, CellHeight int
+	// CenterInCell centers each rectangle within its cell when using
+	// HeuristicGrid, rather than aligning it to the top-left corner.
+	CenterInCell bool
+	// Attempts is the number of orderings tried by PackBest. Zero means 8.
+	Attempts int
+	// Seed seeds the random orderings tried by PackBest.
+	Seed int64
+	// Deadline, if set, bounds the time spent refining a layout. Once it
+	// passes, PackBest starts no further attempts and HeuristicAuto stops
+	// its exhaustive search, and the best layout found so far is used
+	// without an error. The first attempt always runs, so a layout is
+	// returned however early the deadline. Zero means no deadline.
+	Deadline time.Time
+	// Parallelism is the maximum number of attempts PackBest runs
+	// concurrently. Zero means runtime.GOMAXPROCS(0).
+	Parallelism int
+	// Hints gives a preferred position for the rectangle at each index, such
+	// as its position in a previous layout. Among equally scoring candidates,
+	// the one nearest the hint is chosen, which reduces movement between
+	// layouts. Rectangles beyond the end of the slice, or whose hint is
+	// (Unplaced, Unplaced), have no hint.
+	Hints []image.Point
+	// Grid snaps the position of every rectangle to a multiple of Grid, as
+	// chosen by GridRounding, and rounds the dimensions of the layout up to a
+	// multiple of Grid, including when checking MaxWidth and MaxHeight.
+	// Dimensions are always rounded up so that no rectangle is cut off.
+	// HeuristicGrid only rounds the dimensions. Zero or one disables
+	// snapping.
+	Grid int
+	// GridRounding selects how positions snap to Grid.
+	GridRounding GridRounding
+	// AlignTo rounds the dimensions of the layout up to a multiple of
+	// AlignTo, leaving the extra space unused, such as to match the 4x4
+	// blocks of compressed texture formats. Unlike Grid, positions are not
+	// snapped. The rounding counts towards MaxWidth and MaxHeight, and with
+	// Grid the dimensions are multiples of both. Zero or one leaves the
+	// dimensions unchanged.
+	AlignTo int
+	// Quantize rounds the final position of every rectangle to the nearest
+	// multiple of Quantize, such as to shorten a serialized layout. The
+	// multiples are measured from EdgeMargin, or from the origin of the
+	// region holding the rectangle with Regions. Unlike Grid, it is applied
+	// after packing, and a rectangle that would overlap another once rounded
+	// is nudged right or down to later multiples until it is clear. Pinned
+	// rectangles keep their positions, as does a rectangle that would be
+	// rounded out of its region or onto a position an Allower vetoes. The
+	// dimensions of the layout grow to contain the rectangles, which can take
+	// them past MaxWidth and MaxHeight. Zero or one leaves the positions
+	// unchanged.
+	Quantize int
+	// LessIndex reports whether the rectangle at index i should be placed
+	// before the rectangle at index j, like the less function of sort.Slice
+	// but given the original indices, so the order can depend on data held
+	// outside the Packable. Rectangles that compare equal keep their index
+	// order. Nil places the largest rectangles first.
+	LessIndex func(i, j int) bool
+	// CanonicalOrder places the rectangles by descending area, then width,
+	// then height, so that the layout depends only on the sizes of the
+	// rectangles and not on their order: any permutation of the same sizes
+	// gets the same positions, with equal rectangles in index order. It is
+	// ignored if LessIndex is set, and Hints and other per-index settings
+	// can still make the layout depend on the order.
+	CanonicalOrder bool
+	// AutoOrient, if it has both dimensions, is a target frame whose
+	// orientation the layout should match. A landscape layout is rotated by
+	// Layout.Rotate90 to fit a portrait frame, and a portrait layout to fit
+	// a landscape one, unless the rotated layout would exceed MaxWidth or
+	// MaxHeight or stray from the aspect ratio range. The rectangles are
+	// rotated with it, so check for swapped dimensions in the placements. It
+	// is ignored with Regions.
+	AutoOrient Rectangle
+	// YUp reports positions with the y-axis growing upward from the bottom
+	// of the layout, as in OpenGL, so each Y is the distance from the bottom
+	// edge of the layout to the bottom edge of the rectangle. Rectangles with
+	// no area stay at (0, 0). Hints, pins and Regions are still given with
+	// the y-axis growing downward.
+	YUp bool
+	// OrderBy selects the order of the placements in the layout.
+	OrderBy Order
+	// Stats, if set, is reset and then populated with counts of the work
+	// done by PackWithOptions, PackBestEffort, PackBest, PackBins or
+	// PackAutoGrow. PackBest sums the counts over every attempt, and
+	// PackBins and PackAutoGrow over every packing they try.
+	Stats *PackStats
+	// PinReport, if set, is populated with the rectangles that the pinned
+	// rectangles of a Pinner forced to grow the layout. PackBest ignores it.
+	PinReport *PinReport
+	// MaxOverlapRatio lets a rectangle overlap each placed rectangle by up
+	// to this fraction of the smaller one's area, trading overlap for a
+	// smaller layout. Among the best scoring candidates, the one that
+	// overlaps least is chosen. The overlaps are reported in
+	// Layout.Overlaps. Zero means no overlap is allowed.
+	MaxOverlapRatio float64
+	// EdgeMargin keeps every rectangle at least this far from the edges of
+	// the layout, such as to keep parts away from the edge of a sheet. The
+	// first rectangle starts at (EdgeMargin, EdgeMargin), the dimensions
+	// grow by twice the margin, and the margin counts towards MaxWidth and
+	// MaxHeight. It is ignored with Regions, whose positions are already
+	// canvas coordinates.
+	EdgeMargin int
+	// Candidates, if set, supplies the positions evaluated for each
+	// rectangle r in place of those derived from the edges of the existing
+	// placements, such as to snap to guide lines. The placements and the
+	// positions are in the coordinates used while packing, where the first
+	// rectangle is placed at (0, 0) before the layout is shifted. Positions
+	// that break the limits, the alignment or the regions, or overlap a
+	// placement, are skipped, and the rest are ranked as usual. If none is
+	// valid, the rectangle falls back as it would when the search finds
+	// nothing. Nil uses the edges.
+	Candidates func(placements []Placement, r Rectangle) []image.Point
+	// MaxEvalPerRect caps the number of candidate positions evaluated for
+	// each rectangle, bounding the cost of every placement. The best
+	// candidate found within the cap is used; if none is valid, the
+	// rectangle is placed beyond the others, or in the first free slot found
+	// by sweeping the layout row by row when it is bounded. Zero means no
+	// cap.
+	MaxEvalPerRect int
+	// Gravity pushes the rectangles toward a corner once they have all been
+	// placed, moving each in placement order as far as it can go without
+	// overlapping another, which can close gaps left by the packer and
+	// shrink the layout. Positions may leave the grid set by Grid, and
+	// aligned rectangles may lose their alignment. Pinned rectangles do not
+	// move, the rectangles of an Allower only stop at allowed positions, and
+	// it is ignored with Regions and by HeuristicGrid.
+	Gravity Gravity
+	// StopWhen, if set, is called with the layout so far after each
+	// rectangle is placed, and ends packing early when it returns true, such
+	// as once the layout reaches a target size. The remaining rectangles are
+	// left unplaced and treated like those dropped by Limit. Building each
+	// partial layout costs time proportional to the rectangles placed so
+	// far.
+	StopWhen func(partial Layout) bool
+	// CenterIndex is the index of a rectangle to place first, such as the
+	// hero image of a collage, with the others arranged around it on every
+	// side. Each of the others is placed as close as it can be to the center
+	// of that rectangle, and only then by Score, so the layout stays roughly
+	// centered on it. It is only used when Centered is set, and a negative
+	// index disables it.
+	CenterIndex int
+	// Centered enables CenterIndex.
+	Centered bool
+	// RelativeTo is the index of a rectangle that serves as the origin of
+	// the layout, such as the local origin of a scene graph. Every position
+	// is translated so that this rectangle is at (0, 0), and those above or
+	// to the left of it are negative. Rectangles with no area stay at
+	// (0, 0). The dimensions of the layout are unchanged. It is only used
+	// when Relative is set, and has no effect if the rectangle is not
+	// placed. PackBins ignores it, ComposeMulti rejects it, and such layouts
+	// are checked with ValidateLayoutWithOptions rather than ValidateLayout.
+	RelativeTo int
+	// Relative enables RelativeTo.
+	Relative bool
+	// KeepPinPositions keeps the coordinates of the pinned rectangles of a
+	// Pinner, which may be negative, rather than shifting the layout so that
+	// it starts at (0, 0). The other rectangles are positioned in the same
+	// coordinate space, around the pins, and the dimensions of the layout
+	// are those of its bounding box, whose top-left corner is at the
+	// smallest X and Y among the placements, less the EdgeMargin. Without
+	// pins, the first rectangle is placed at (0, 0).
+	KeepPinPositions bool
+	// Strict makes the packer fail rather than fall back when the candidate
+	// search finds no position for a rectangle. Unbounded layouts normally
+	// place such a rectangle beyond the others, and bounded layouts retry
+	// without alignment and then sweep for any free slot. With Strict, the
+	// rectangle is left unplaced instead, and PackWithOptions returns
+	// ErrNoCandidate.
+	Strict bool
+	// MinimizeBins makes PackBins search for the fewest bins when there are
+	// at most 8 rectangles, rather than filling the bins greedily. It is
+	// ignored when BinSizes is set.
+	MinimizeBins bool
+	// BinSizes gives the dimensions of the bins opened by PackBins in turn,
+	// such as the texture sizes supported by a range of devices, in place of
+	// MaxWidth and MaxHeight. A size that none of the remaining rectangles
+	// fit in is passed over, and the last size is used for every bin beyond
+	// the end of the slice. Empty sizes every bin by MaxWidth and MaxHeight.
+	BinSizes []Rectangle
+	// Regions describes a canvas made of several areas, such as an L-shaped
+	// page beside a fixed sidebar. Rectangles are only placed where they lie
+	// entirely within the union of the regions, and are left unplaced if
+	// there is no room. Positions are canvas coordinates rather than being
+	// shifted to start at (0, 0), so the dimensions of the layout are
+	// measured from the origin of the canvas. HeuristicGrid ignores the
+	// regions. Empty means a single unbounded region.
+	Regions []image.Rectangle
+}
+
+// bounded returns true if the options limit the size of the layout.
+func (o Options) bounded() bool {
+	return o.MaxWidth > 0 || o.MaxHeight > 0 || len(o.Regions) > 0
+}
+
+// contains returns true if the placement lies within the regions.
+func (o Options) contains(p placement) bool {
+	if len(o.Regions) == 0 {
+		return true
+	}
+	return covers(o.Regions, image.Rect(p.x, p.y, p.x+p.width, p.y+p.height))
+}
+
+// fits returns true if b is within the width and height limits.
+func (o Options) fits(b bounds) bool {
+	if o.MaxWidth > 0 && o.extent(b.maxX-b.minX+2*o.margin()) > o.MaxWidth {
+		return false
+	}
+	if o.MaxHeight > 0 && o.extent(b.maxY-b.minY+2*o.margin()) > o.MaxHeight {
+		return false
+	}
+	return true
+}
+
+// dimensions returns the bounds of a layout that holds everything in b,
+// starting at the origin and including the edge margin and grid rounding.
+func (o Options) dimensions(b bounds) bounds {
+	return bounds{maxX: o.extent(b.maxX - b.minX + 2*o.margin()), maxY: o.extent(b.maxY - b.minY + 2*o.margin())}
+}
+
+// margin returns the space kept clear around the edges of the layout.
+func (o Options) margin() int {
+	if len(o.Regions) > 0 {
+		return 0
+	}
+	return max(o.EdgeMargin, 0)
+}
+
+// aspectPenalty returns the area that b would need to grow by to bring its
+// aspect ratio within the range set by MinAspect and MaxAspect. Returns zero
+// if b is already within the range.
+func (o Options) aspectPenalty(b bounds) int64 {
+	var width, height = int64(b.maxX - b.minX), int64(b.maxY - b.minY)
+	if width == 0 || height == 0 {
+		return 0
+	}
+	var aspect = float64(width) / float64(height)
+	if o.MaxAspect > 0 && aspect > o.MaxAspect {
+		var needed = int64(math.Ceil(float64(width) / o.MaxAspect))
+		return (needed - height) * width
+	}
+	if o.MinAspect > 0 && aspect < o.MinAspect {
+		var needed = int64(math.Ceil(float64(height) * o.MinAspect))
+		return (needed - width) * height
+	}
+	return 0
+}
+
+// snap rounds the coordinate v to a multiple of the grid as configured by
+// GridRounding.
+func (o Options) snap(v int) int {
+	return roundToGrid(v, o.Grid, o.GridRounding)
+}
+
+// extent rounds the dimension v up to a multiple of the grid and of AlignTo.
+func (o Options) extent(v int) int {
+	for {
+		var rounded = roundToGrid(roundToGrid(v, o.Grid, RoundUp), o.AlignTo, RoundUp)
+		if rounded == v {
+			return v
+		}
+		v = rounded
+	}
+}
+
+// roundToGrid rounds v to a multiple of grid in the given direction. A grid
+// of one or less leaves v unchanged.
+func roundToGrid(v, grid int, rounding GridRounding) int {
+	if grid <= 1 {
+		return v
+	}
+	var down = v - ((v%grid)+grid)%grid
+	switch {
+	case down == v:
+		return v
+	case rounding == RoundDown:
+		return down
+	case rounding == RoundNearest && 2*(v-down) < grid:
+		return down
+	default:
+		return down + grid
+	}
+}
+
+// centerIndex returns the index set by CenterIndex, or -1 if it is disabled.
+func (o Options) centerIndex() int {
+	if !o.Centered || o.CenterIndex < 0 {
+		return -1
+	}
+	return o.CenterIndex
+}
+
+// orient rotates the layout to match the orientation of AutoOrient, if it
+// is set and the rotated layout stays within the limits.
+func (o Options) orient(l Layout) Layout {
+	var target = o.AutoOrient
+	if target.Width <= 0 || target.Height <= 0 || target.Width == target.Height || l.Width == l.Height || len(o.Regions) > 0 {
+		return l
+	}
+	if (l.Width > l.Height) == (target.Width > target.Height) {
+		return l
+	}
+	if (o.MaxWidth > 0 && l.Height > o.MaxWidth) || (o.MaxHeight > 0 && l.Width > o.MaxHeight) {
+		return l
+	}
+	if o.aspectPenalty(bounds{maxX: l.Height, maxY: l.Width}) > o.aspectPenalty(bounds{maxX: l.Width, maxY: l.Height}) {
+		return l
+	}
+	return l.Rotate90()
+}
+
+// constraintsFor returns the constraints set by the options for the rectangle
+// at index n.
+func (o Options) constraintsFor(n int) constraints {
+	var c constraints
+	if n < len(o.Hints) && o.Hints[n] != image.Pt(Unplaced, Unplaced) {
+		c.hint = &o.Hints[n]
+	}
+	return c
+}
+
+// relative translates the layout so that the rectangle set by RelativeTo is
+// at (0, 0), if Relative is set and the rectangle is placed. A rectangle that
+// shares the placement of another through Layout.Refs uses that placement.
+func (o Options) relative(l Layout) Layout {
+	if !o.Relative {
+		return l
+	}
+	var anchor = o.RelativeTo
+	for _, ref := range l.Refs {
+		if ref.Index == anchor {
+			anchor = ref.Ref
+		}
+	}
+	var i = slices.IndexFunc(l.Placements, func(p Placement) bool { return p.Index == anchor })
+	if i < 0 || (l.Placements[i].X == 0 && l.Placements[i].Y == 0) {
+		return l
+	}
+
+	var dx, dy = l.Placements[i].X, l.Placements[i].Y
+	l.Placements = slices.Clone(l.Placements)
+	for i, p := range l.Placements {
+		// Rectangles with no area stay at the origin.
+		if p.Width > 0 && p.Height > 0 {
+			l.Placements[i].X, l.Placements[i].Y = p.X-dx, p.Y-dy
+		}
+	}
+	if l.Cells != nil {
+		l.Cells = slices.Clone(l.Cells)
+		for i := range l.Cells {
+			l.Cells[i] = l.Cells[i].Sub(image.Pt(dx, dy))
+		}
+	}
+	return l
+}
+
+// expired reports whether the Deadline has passed.
+func (o Options) expired() bool {
+	return !o.Deadline.IsZero() && time.Now().After(o.Deadline)
+}
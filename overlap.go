@@ -0,0 +1,77 @@
+package binpack
+
+import (
+	"image"
+	"sort"
+)
+
+// OverlapPair describes two rectangles in a layout that share some area, which
+// only happens when Options.MaxOverlapRatio allows it.
+type OverlapPair struct {
+	// A and B are the indices of the overlapping rectangles, with A < B.
+	A, B int
+	// Area is the area they share.
+	Area int64
+}
+
+// Overlap returns true if rectangles a and b share any area, using the same
+// rule as the packer: rectangles that only touch along an edge do not
+// overlap. It lets tests of a Packable check a layout the way the packer does.
+func Overlap(a, b image.Rectangle) bool {
+	return doRectanglesIntersect(
+		placement{x: a.Min.X, y: a.Min.Y, width: a.Dx(), height: a.Dy()},
+		placement{x: b.Min.X, y: b.Min.Y, width: b.Dx(), height: b.Dy()},
+	)
+}
+
+// overlapArea returns the area shared by placements a and b.
+func overlapArea(a, b placement) int64 {
+	var width = min(a.x+a.width, b.x+b.width) - max(a.x, b.x)
+	var height = min(a.y+a.height, b.y+b.height) - max(a.y, b.y)
+	if width <= 0 || height <= 0 {
+		return 0
+	}
+	return int64(width) * int64(height)
+}
+
+// overlap returns the total area by which candidate overlaps the placements,
+// or false if it overlaps any of them by more than MaxOverlapRatio of the
+// smaller of the two.
+func (o Options) overlap(candidate placement, placements []placement) (int64, bool) {
+	if o.MaxOverlapRatio <= 0 {
+		return 0, !hasIntersection(candidate, placements, o.Stats)
+	}
+	o.Stats.countIntersectionTests(len(placements))
+	var total int64
+	for _, p := range placements {
+		var area = overlapArea(candidate, p)
+		if area == 0 {
+			continue
+		}
+		if float64(area) > o.MaxOverlapRatio*float64(min(candidate.area(), p.area())) {
+			return 0, false
+		}
+		total += area
+	}
+	return total, true
+}
+
+// collectOverlaps returns every pair of placements that share some area,
+// ordered by index.
+func collectOverlaps(placements []placement) []OverlapPair {
+	var overlaps []OverlapPair
+	for i, a := range placements {
+		for _, b := range placements[i+1:] {
+			if area := overlapArea(a, b); area > 0 {
+				overlaps = append(overlaps, OverlapPair{A: min(a.position, b.position), B: max(a.position, b.position), Area: area})
+			}
+		}
+	}
+	sort.Slice(overlaps, func(i, j int) bool {
+		if overlaps[i].A != overlaps[j].A {
+			return overlaps[i].A < overlaps[j].A
+		}
+		return overlaps[i].B < overlaps[j].B
+	})
+	return overlaps
+}
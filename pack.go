@@ -1,7 +1,11 @@
 package binpack
 
 import (
+	"errors"
+	"fmt"
+	"image"
 	"math"
+	"slices"
 	"sort"
 )
 
@@ -15,6 +19,28 @@ func (r Rectangle) Area() int {
 	return r.Width * r.Height
 }
 
+// area64 returns the area of the rectangle computed in 64 bits, so that it
+// does not overflow on 32-bit platforms.
+func (r Rectangle) area64() int64 {
+	return int64(r.Width) * int64(r.Height)
+}
+
+// Contains returns true if the point (x, y) lies within the rectangle placed
+// at the origin. It includes the top-left edges but not the bottom-right
+// edges, so a rectangle with no area contains no points.
+func (r Rectangle) Contains(x, y int) bool {
+	return x >= 0 && x < r.Width && y >= 0 && y < r.Height
+}
+
+// Scale returns the rectangle with its dimensions multiplied by factor and
+// rounded to the nearest integer.
+func (r Rectangle) Scale(factor float64) Rectangle {
+	return Rectangle{
+		Width:  int(math.Round(float64(r.Width) * factor)),
+		Height: int(math.Round(float64(r.Height) * factor)),
+	}
+}
+
 // Packable is the interface for types that support rectangle packing.
 type Packable interface {
 	Len() int
@@ -22,77 +48,594 @@ type Packable interface {
 	Place(n, x, y int)
 }
 
+// rectangleSlice adapts a slice of rectangles to the Packable interface.
+// Placements are discarded; callers read them from the resulting Layout.
+type rectangleSlice []Rectangle
+
+// Len returns the number of rectangles.
+func (s rectangleSlice) Len() int {
+	return len(s)
+}
+
+// Rectangle returns the rectangle at index n.
+func (s rectangleSlice) Rectangle(n int) Rectangle {
+	return s[n]
+}
+
+// Place discards the placement of the rectangle at index n.
+func (s rectangleSlice) Place(int, int, int) {}
+
 // placement represents a rectangle placed at a specific position.
 type placement struct {
 	position, x, y, width, height int
 }
 
+// area returns the area of the placement computed in 64 bits.
+func (p placement) area() int64 {
+	return int64(p.width) * int64(p.height)
+}
+
 // bounds represents the bounding box for a set of rectangles.
 type bounds struct {
 	minX, minY, maxX, maxY int
 }
 
+// ErrTooLarge is returned when the rectangles cannot be packed within the
+// limits set by Options.
+var ErrTooLarge = errors.New("binpack: rectangles do not fit within the maximum dimensions")
+
+// ErrNoCandidate is returned in strict mode when no candidate position is
+// found for a rectangle, rather than placing it at a fallback position.
+var ErrNoCandidate = errors.New("binpack: no candidate position found")
+
+// ErrAspectRatio is returned when the layout cannot be kept within the aspect
+// ratio range set by Options.
+var ErrAspectRatio = errors.New("binpack: layout does not fit within the aspect ratio range")
+
+// PartialError is implemented by the errors returned by PackWithOptions, to
+// give the layout it computed before failing, such as for a preview. The
+// layout holds the rectangles that could be placed, and is never applied.
+// Use errors.As to retrieve it.
+type PartialError interface {
+	error
+	// Partial returns the layout of the rectangles that could be placed.
+	Partial() Layout
+}
+
+// partialError wraps an error of PackWithOptions with the partial layout.
+type partialError struct {
+	err    error
+	layout Layout
+}
+
+// Error returns the message of the wrapped error.
+func (e *partialError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error, so errors.Is matches it.
+func (e *partialError) Unwrap() error {
+	return e.err
+}
+
+// Partial returns the layout of the rectangles that could be placed.
+func (e *partialError) Partial() Layout {
+	return e.layout
+}
+
 // Pack arranges rectangles into a compact layout. Larger rectangles are
 // placed first to reduce conflicts. The final layout is shifted so that its
 // top-left corner is at (0, 0). Returns the overall dimensions.
+//
+// Rectangles with zero area, such as placeholders, occupy no space: they are
+// placed at (0, 0) and do not contribute to the overall dimensions.
 func Pack(p Packable) (int, int) {
-	var count = p.Len()
-	if count == 0 {
-		return 0, 0
+	var layout, _, _ = pack(p, Options{})
+	layout.apply(p)
+	return layout.Width, layout.Height
+}
+
+// PackWithOptions arranges rectangles into a compact layout using the provided
+// options. If any rectangle cannot be placed within the limits, ErrTooLarge is
+// returned, and if the layout falls outside the aspect ratio range,
+// ErrAspectRatio is returned. With Options.Strict, ErrNoCandidate is returned
+// instead of ErrTooLarge, listing the rectangles without a candidate. When
+// the layout is unbounded, rectangles of an Allower left unplaced because
+// every position was vetoed are listed with ErrNotAllowed instead. No
+// rectangles are placed when an error is returned, but these errors implement
+// PartialError to give the layout of those that could be. With
+// HeuristicFilmstrip, ErrUnevenHeight is returned before packing if the
+// rectangles differ in height.
+func PackWithOptions(p Packable, opts Options) (Layout, error) {
+	defer opts.Stats.begin()()
+	if opts.Heuristic == HeuristicFilmstrip {
+		if err := uniformHeight(p); err != nil {
+			return Layout{}, err
+		}
+	}
+	var layout, unplaced, _ = pack(p, opts)
+	if len(unplaced) > 0 && opts.Strict {
+		return Layout{}, &partialError{err: fmt.Errorf("%w: rectangles %v", ErrNoCandidate, unplaced), layout: layout}
+	}
+	if _, ok := optional[Allower](p); ok && len(unplaced) > 0 && !opts.bounded() {
+		return Layout{}, &partialError{err: fmt.Errorf("%w: rectangles %v", ErrNotAllowed, unplaced), layout: layout}
+	}
+	if len(unplaced) > 0 {
+		return Layout{}, &partialError{err: ErrTooLarge, layout: layout}
+	}
+	if opts.aspectPenalty(bounds{maxX: layout.Width, maxY: layout.Height}) > 0 {
+		return Layout{}, &partialError{err: ErrAspectRatio, layout: layout}
+	}
+	layout.apply(p)
+	return layout, nil
+}
+
+// PackBestEffort arranges as many rectangles as possible within the limits set
+// by opts. Rectangles that fit are placed, and the indices of those that did
+// not fit, or were dropped by opts.Limit, are returned in ascending order.
+func PackBestEffort(p Packable, opts Options) (Layout, []int) {
+	defer opts.Stats.begin()()
+	var layout, unplaced, dropped = pack(p, opts)
+	layout.apply(p)
+	if len(dropped) > 0 {
+		unplaced = append(unplaced, dropped...)
+		sort.Ints(unplaced)
+	}
+	return layout, unplaced
+}
+
+// pack computes the layout for the rectangles in p without placing them.
+// Returns the layout, the indices of the rectangles that could not be placed,
+// and the indices of the rectangles that were dropped by opts.Limit.
+func pack(p Packable, opts Options) (Layout, []int, []int) {
+	var layout, unplaced, dropped = arrange(p, opts)
+	return opts.relative(layout), unplaced, dropped
+}
+
+// arrange computes the layout for pack with the algorithm selected by p and
+// opts.
+func arrange(p Packable, opts Options) (Layout, []int, []int) {
+	if p.Len() == 0 {
+		return Layout{}, nil, nil
+	}
+	if refs := collectInstances(p); len(refs) > 0 {
+		return packInstances(p, opts, refs)
+	}
+	if pairs := collectMirrorPairs(p); len(pairs) > 0 {
+		return packMirrored(p, opts, pairs)
+	}
+	switch opts.Heuristic {
+	case HeuristicGrid:
+		return packGrid(p, opts)
+	case HeuristicAuto:
+		return packAuto(p, opts)
+	case HeuristicFilmstrip:
+		return packFilmstrip(p, opts)
 	}
+	return packAround(p, opts, nil, bounds{})
+}
+
+// packAround computes the layout for the rectangles in p around the existing
+// placements, whose bounding box is b. The existing placements are never
+// moved and are included in the layout ahead of the new ones.
+func packAround(p Packable, opts Options, placements []placement, b bounds) (Layout, []int, []int) {
+	return packOrdered(p, opts, sortPositions(p, opts), placements, b)
+}
+
+// sortPositions returns the indices of the rectangles in p in the order they
+// are placed: by opts.LessIndex if it is set, then by size if
+// opts.CanonicalOrder is set, and otherwise largest first.
+func sortPositions(p Packable, opts Options) []int {
+	var count = p.Len()
 
 	var positions = make([]int, count)
 	for i := 0; i < count; i++ {
 		positions[i] = i
 	}
 
+	if opts.LessIndex != nil {
+		sort.SliceStable(positions, func(i, j int) bool {
+			return opts.LessIndex(positions[i], positions[j])
+		})
+		return positions
+	}
+
+	// Order by size alone, so that rectangles are only tied when they are interchangeable.
+	if opts.CanonicalOrder {
+		sort.SliceStable(positions, func(i, j int) bool {
+			var a, b = p.Rectangle(positions[i]), p.Rectangle(positions[j])
+			if a.area64() != b.area64() {
+				return a.area64() > b.area64()
+			}
+			if a.Width != b.Width {
+				return a.Width > b.Width
+			}
+			return a.Height > b.Height
+		})
+		return positions
+	}
+
 	// Sort the positions to prioritize larger rectangles first.
 	sort.Slice(positions, func(i, j int) bool {
-		return p.Rectangle(positions[i]).Area() > p.Rectangle(positions[j]).Area()
+		return p.Rectangle(positions[i]).area64() > p.Rectangle(positions[j]).area64()
 	})
+	return positions
+}
+
+// SortByArea sorts rects in place, largest first, in the same order that Pack
+// places them.
+func SortByArea(rects []Rectangle) {
+	sort.Slice(rects, func(i, j int) bool {
+		return rects[i].area64() > rects[j].area64()
+	})
+}
 
-	var placements []placement
+// packOrdered computes the layout like packAround, but places the rectangles
+// in the order given by positions.
+func packOrdered(p Packable, opts Options, positions []int, placements []placement, b bounds) (Layout, []int, []int) {
+	// Leave out the gaps, and remember which rectangles will be reached.
+	positions = slices.DeleteFunc(slices.Clone(positions), skipper(p))
+	var included = make(map[int]bool, len(positions))
 	for _, position := range positions {
-		var rectangle = p.Rectangle(position)
+		included[position] = true
+	}
+
+	// Place the pinned rectangles first, as obstacles for the others.
+	var center *image.Point
+	var unpinned = append(make([]placement, 0, len(placements)+len(positions)), placements...)
+	var pins = collectPins(p, positions)
+	var located = make(map[int]placement)
+	var reached = make(map[int]bool)
+	for _, pin := range pins {
+		positions = slices.DeleteFunc(positions, func(n int) bool { return n == pin.position })
 		if len(placements) == 0 {
-			placements = append(placements, placement{
-				position: position,
-				x:        0,
-				y:        0,
-				width:    rectangle.Width,
-				height:   rectangle.Height,
-			})
+			b = bounds{minX: pin.x, minY: pin.y, maxX: pin.x + pin.width, maxY: pin.y + pin.height}
+		} else {
+			b = expandBoundsForPlacement(pin, b)
+		}
+		placements = append(placements, pin)
+		located[pin.position] = pin
+		reached[pin.position] = true
+		if pin.position == opts.centerIndex() {
+			center = &image.Point{X: pin.x + pin.width/2, Y: pin.y + pin.height/2}
+		}
+	}
+	var report PinReport
+	var fixed = len(placements)
+
+	// Place the center rectangle before the others so they can be arranged around it.
+	var hero = opts.centerIndex()
+	if i := slices.Index(positions, hero); i > 0 {
+		positions = slices.Insert(slices.Delete(positions, i, i+1), 0, hero)
+	}
+
+	// Place the backgrounds before everything else, and let the foreground overlap them.
+	var background = backgrounder(p)
+	var _, layered = optional[Backgrounder](p)
+	var foreground []placement
+	if layered {
+		slices.SortStableFunc(positions, func(a, b int) int {
+			switch {
+			case background(a) == background(b):
+				return 0
+			case background(a):
+				return -1
+			}
+			return 1
+		})
+		foreground = make([]placement, 0, len(placements)+len(positions))
+		for _, placed := range placements {
+			if !background(placed.position) {
+				foreground = append(foreground, placed)
+			}
+		}
+	}
+
+	// Track the candidate positions incrementally as rectangles are placed.
+	var candidates = newCandidateSet(placements, opts.Regions)
+
+	// Collect the alternatives for rectangles in exclusive groups.
+	var groups = collectGroups(p)
+
+	var unplaced, excluded, dropped, empty []int
+	var resolved = make(map[int]bool)
+	var attempted int
+
+	// Let StopWhen end packing early once it is satisfied with the layout so far.
+	var stopped bool
+	var stop = func() {
+		if opts.StopWhen != nil && !stopped {
+			stopped = opts.StopWhen(newLayout(p, opts, placements, empty, nil, b))
+		}
+	}
+
+	// Track the rectangles that have been reached so aligned rectangles can wait for their anchor.
+	var aligner, _ = optional[Aligner](p)
+	var centralizer, _ = optional[Centralizer](p)
+	var flexible, _ = optional[Flexible](p)
+	var radii = cornerRadii(p)
+	var pending = make(map[int][]int)
+	var i int
+	var release = func(n int) {
+		reached[n] = true
+		if dependents, ok := pending[n]; ok {
+			positions = slices.Insert(positions, i+1, dependents...)
+			delete(pending, n)
+		}
+	}
+
+	for i = 0; i < len(positions); i++ {
+		var position = positions[i]
+		if resolved[position] {
+			continue
+		}
+
+		// Defer aligned rectangles until their anchor has been reached.
+		var c = opts.constraintsFor(position)
+		c.center = center
+		c.allowed = allowedAt(p, position)
+		if radii != nil {
+			c.radius, c.radii = radii(position), radii
+		}
+		if centralizer != nil {
+			c.centrality = min(max(centralizer.Centrality(position), 0), 1)
+		}
+		if layered && !background(position) {
+			c.blocking = foreground
+		}
+		if aligner != nil {
+			if other, axis, ok := aligner.AlignWith(position); ok && other != position && included[other] {
+				if !reached[other] {
+					reached[position] = true
+					pending[other] = append(pending[other], position)
+					continue
+				}
+				if anchor, ok := located[other]; ok {
+					c.align = alignment{axis: axis, anchor: anchor, active: true}
+				}
+			}
+		}
+		release(position)
+
+		// Drop the remaining rectangles once the limit has been reached or StopWhen has stopped packing.
+		if stopped || (opts.Limit > 0 && attempted >= opts.Limit) {
+			if alternatives, ok := groups[position]; ok {
+				for _, alternative := range alternatives {
+					resolved[alternative] = true
+				}
+				dropped = append(dropped, alternatives...)
+			} else {
+				dropped = append(dropped, position)
+			}
 			continue
 		}
+		attempted++
+		opts.Stats.countPlacement()
+
+		var rectangle Rectangle
+		var bestX, bestY int
+		var candidateFound bool
+		if alternatives, ok := groups[position]; ok {
+			// Let the alternatives in the group compete for the placement.
+			var winner int
+			winner, rectangle, bestX, bestY, candidateFound = chooseAlternative(p, alternatives, placements, candidates, b, opts)
+			for _, alternative := range alternatives {
+				resolved[alternative] = true
+				release(alternative)
+				if candidateFound && alternative != winner {
+					excluded = append(excluded, alternative)
+				}
+			}
+			if !candidateFound {
+				unplaced = append(unplaced, alternatives...)
+				continue
+			}
+			position = winner
+		} else {
+			// Choose the candidate that minimizes the overall bounding box and is as centered as possible.
+			rectangle = p.Rectangle(position)
+			bestX, bestY, candidateFound = locate(rectangle, placements, candidates, b, c, opts)
+			if !candidateFound && flexible != nil {
+				rectangle, bestX, bestY, candidateFound = shrinkToFit(flexible, position, placements, candidates, b, c, opts)
+			}
+			if !candidateFound {
+				unplaced = append(unplaced, position)
+				continue
+			}
 
-		// Derive candidate positions from existing rectangle edges.
-		var xCandidates, yCandidates = getCandidatePositions(placements)
-		var bounds = computeBounds(placements)
+			// Measure how much the pins cost this rectangle, if asked.
+			if opts.PinReport != nil && len(pins) > 0 && rectangle.area64() > 0 {
+				if growth := displacement(rectangle, bestX, bestY, placements, candidates, unpinned, b, c, opts); growth > 0 {
+					report.Displaced = append(report.Displaced, Displacement{Index: position, Growth: growth})
+					report.Growth += growth
+				}
+			}
+		}
 
-		// Choose the candidate that minimizes the overall bounding box and is as centered as possible.
-		var bestX, bestY, candidateFound = findBestPlacement(xCandidates, yCandidates, bounds, rectangle, placements)
-		if !candidateFound {
-			bestX = bounds.maxX
-			bestY = bounds.minY
+		// Rectangles with no area occupy no space, so they are kept out of the
+		// bounding box and placed at the origin of the final layout.
+		if rectangle.area64() == 0 {
+			empty = append(empty, position)
+			stop()
+			continue
 		}
 
-		placements = append(placements, placement{
+		var placed = placement{
 			position: position,
 			x:        bestX,
 			y:        bestY,
 			width:    rectangle.Width,
 			height:   rectangle.Height,
-		})
+		}
+
+		// Maintain the bounding box incrementally rather than rescanning every placement.
+		if len(placements) == 0 {
+			b = bounds{minX: placed.x, minY: placed.y, maxX: placed.x + placed.width, maxY: placed.y + placed.height}
+		} else {
+			b = expandBoundsForPlacement(placed, b)
+		}
+		placements = append(placements, placed)
+		if layered && !background(position) {
+			foreground = append(foreground, placed)
+		}
+		candidates.add(placed)
+		unpinned = append(unpinned, placed)
+		located[position] = placed
+		if position == hero {
+			center = &image.Point{X: placed.x + placed.width/2, Y: placed.y + placed.height/2}
+		}
+		stop()
+	}
+	if opts.PinReport != nil {
+		*opts.PinReport = report
+	}
+	sort.Ints(unplaced)
+	sort.Ints(excluded)
+	sort.Ints(dropped)
+
+	if len(placements) == 0 && len(empty) == 0 {
+		return Layout{excluded: excluded}, unplaced, dropped
+	}
+
+	// Push the placed rectangles toward the corner chosen by Gravity, leaving fixed and pinned ones in place.
+	if opts.Gravity != GravityNone && len(opts.Regions) == 0 && len(placements) > fixed {
+		var settled = slices.Clone(placements)
+		var allowed func(n, x, y int) bool
+		if a, ok := optional[Allower](p); ok {
+			allowed = a.Allowed
+		}
+		settle(settled, fixed, opts.Gravity, b, allowed)
+		var sb = bounds{minX: settled[0].x, minY: settled[0].y, maxX: settled[0].x + settled[0].width, maxY: settled[0].y + settled[0].height}
+		for _, placed := range settled[1:] {
+			sb = expandBoundsForPlacement(placed, sb)
+		}
+
+		// Keep the original positions if shrinking the layout would take it out of the aspect ratio range.
+		if opts.aspectPenalty(opts.dimensions(sb)) <= opts.aspectPenalty(opts.dimensions(b)) {
+			placements, b = settled, sb
+		}
+	}
+
+	var layout = newLayout(p, opts, placements, empty, excluded, b)
+	if layered {
+		for _, placed := range placements {
+			if background(placed.position) {
+				layout.Backgrounds = append(layout.Backgrounds, placed.position)
+			}
+		}
 	}
+	return layout, unplaced, dropped
+}
 
-	// Place all of rectangles at their final positions.
-	var bounds = computeBounds(placements)
+// newLayout returns the layout holding placements, whose bounding box is b,
+// and the rectangles with no area in empty, shifted so that it starts at the
+// edge margin unless the placements are in canvas coordinates or keep the
+// coordinates of the pins.
+func newLayout(p Packable, opts Options, placements []placement, empty, excluded []int, b bounds) Layout {
+	var margin int
+	if len(placements) > 0 {
+		margin = opts.margin()
+	}
+	if len(opts.Regions) > 0 {
+		b.minX, b.minY = 0, 0
+	}
+	var layout = Layout{
+		Width:      opts.extent(b.maxX - b.minX + 2*margin),
+		Height:     opts.extent(b.maxY - b.minY + 2*margin),
+		Placements: make([]Placement, 0, len(placements)+len(empty)),
+		excluded:   excluded,
+	}
+	var originX, originY = b.minX - margin, b.minY - margin
+	if opts.KeepPinPositions {
+		originX, originY = 0, 0
+	}
 	for _, placement := range placements {
-		p.Place(placement.position, placement.x-bounds.minX, placement.y-bounds.minY)
+		layout.Placements = append(layout.Placements, Placement{
+			Index:  placement.position,
+			X:      placement.x - originX,
+			Y:      placement.y - originY,
+			Width:  placement.width,
+			Height: placement.height,
+		})
+	}
+	for _, position := range empty {
+		var rectangle = p.Rectangle(position)
+		layout.Placements = append(layout.Placements, Placement{
+			Index:  position,
+			Width:  rectangle.Width,
+			Height: rectangle.Height,
+		})
+	}
+	if opts.MaxOverlapRatio > 0 {
+		layout.Overlaps = collectOverlaps(placements)
+	}
+	if opts.OrderBy == OrderInput {
+		sort.Slice(layout.Placements, func(i, j int) bool {
+			return layout.Placements[i].Index < layout.Placements[j].Index
+		})
+	}
+	layout = layout.quantize(p, opts, originX, originY)
+	layout.Width, layout.Height = opts.extent(layout.Width), opts.extent(layout.Height)
+	layout = opts.orient(layout)
+	if opts.YUp {
+		layout.flipY()
 	}
+	return layout
+}
+
+// constraints holds the requirements and preferences of a single rectangle
+// that are used when locating it. If blocking is not nil, candidates only
+// need to avoid those placements rather than all of them. If center is not
+// nil, candidates are pulled toward it rather than the center of the bounding
+// box. A positive centrality pulls candidates toward the center of the
+// bounding box in proportion. If allowed is not nil, only the positions it
+// allows are considered. If radii is not nil, the rectangle has corners of
+// the given radius and radii gives those of the placements.
+type constraints struct {
+	align      alignment
+	hint       *image.Point
+	blocking   []placement
+	center     *image.Point
+	centrality float64
+	allowed    func(x, y int) bool
+	radius     int
+	radii      func(n int) int
+}
 
-	// Return the overall dimensions.
-	return bounds.maxX - bounds.minX, bounds.maxY - bounds.minY
+// locate finds the position for rectangle r given the existing placements and
+// their bounding box b. If c has an active alignment, only positions that
+// satisfy it are considered unless none fit within the limits, in which case
+// the alignment is dropped, and then a shelf sweep looks for any free slot.
+// Returns false if the rectangle cannot be placed within the limits in opts.
+func locate(r Rectangle, placements []placement, candidates *candidateSet, b bounds, c constraints, opts Options) (int, int, bool) {
+	if r.area64() == 0 {
+		return 0, 0, true
+	}
+	if len(placements) == 0 && len(opts.Regions) == 0 {
+		return 0, 0, opts.fits(bounds{maxX: r.Width, maxY: r.Height}) && c.permits(0, 0)
+	}
+
+	var bestX, bestY, candidateFound = findBestPlacement(b, r, placements, candidates, c, opts)
+	if !candidateFound {
+		if opts.Strict {
+			return 0, 0, false
+		}
+		// Bounded layouts cannot grow to make room, so the rectangle is left unplaced unless a sweep finds a free slot.
+		if opts.bounded() {
+			if c.align.active {
+				c.align = alignment{}
+				return locate(r, placements, candidates, b, c, opts)
+			}
+			return shelfSweep(r, placements, candidates, c, opts)
+		}
+		// The fallback lies beyond the placements, so snapping it up cannot cause an overlap.
+		bestX, bestY = c.align.fallback(b)
+		bestX, bestY = roundToGrid(bestX, opts.Grid, RoundUp), roundToGrid(bestY, opts.Grid, RoundUp)
+		if !c.permits(bestX, bestY) {
+			return 0, 0, false
+		}
+	}
+	return bestX, bestY, true
 }
 
 // expandBoundsForPlacement expands b to include rectangle r.
@@ -112,53 +655,62 @@ func expandBoundsForPlacement(r placement, b bounds) bounds {
 	return b
 }
 
-// computeBounds returns the minimal bounding box enclosing all rectangles.
-func computeBounds(placements []placement) bounds {
-	var b = bounds{
-		minX: placements[0].x,
-		minY: placements[0].y,
-		maxX: placements[0].x + placements[0].width,
-		maxY: placements[0].y + placements[0].height,
+// center returns the center of b, rounded toward its top-left corner.
+func (b bounds) center() image.Point {
+	return image.Point{X: b.minX + (b.maxX-b.minX)/2, Y: b.minY + (b.maxY-b.minY)/2}
+}
+
+// candidateSet holds the edges of the placed rectangles and the corners of the
+// regions, from which candidate positions are derived. The edges are kept
+// sorted and free of duplicates as placements are added, so the candidates are
+// visited in a deterministic order without rebuilding the set for every
+// rectangle.
+type candidateSet struct {
+	xEdges, yEdges []int
+}
+
+// newCandidateSet returns the candidate set for the existing placements and
+// the regions.
+func newCandidateSet(placements []placement, regions []image.Rectangle) *candidateSet {
+	var s = &candidateSet{
+		xEdges: make([]int, 0, 2*len(placements)+len(regions)),
+		yEdges: make([]int, 0, 2*len(placements)+len(regions)),
 	}
-	// Iterate over all placements, expand the bounding box if necessary.
 	for _, r := range placements {
-		if r.x < b.minX {
-			b.minX = r.x
-		}
-		if r.y < b.minY {
-			b.minY = r.y
-		}
-		if r.x+r.width > b.maxX {
-			b.maxX = r.x + r.width
-		}
-		if r.y+r.height > b.maxY {
-			b.maxY = r.y + r.height
-		}
+		s.add(r)
 	}
-	return b
+	for _, region := range regions {
+		s.xEdges = insertEdge(s.xEdges, region.Min.X)
+		s.yEdges = insertEdge(s.yEdges, region.Min.Y)
+	}
+	return s
 }
 
-// getCandidatePositions extracts unique x and y coordinates from the edges of placed rectangles.
-func getCandidatePositions(rects []placement) ([]int, []int) {
-	var x, y = make(map[int]bool), make(map[int]bool)
-	for _, r := range rects {
-		x[r.x] = true
-		x[r.x+r.width] = true
-		y[r.y] = true
-		y[r.y+r.height] = true
-	}
+// add inserts the edges of r into the set.
+func (s *candidateSet) add(r placement) {
+	s.xEdges = insertEdge(insertEdge(s.xEdges, r.x), r.x+r.width)
+	s.yEdges = insertEdge(insertEdge(s.yEdges, r.y), r.y+r.height)
+}
 
-	var xCandidates []int
-	for x := range x {
-		xCandidates = append(xCandidates, x)
+// insertEdge inserts v into the sorted edges unless it is already present.
+func insertEdge(edges []int, v int) []int {
+	var i, found = slices.BinarySearch(edges, v)
+	if found {
+		return edges
 	}
+	return slices.Insert(edges, i, v)
+}
 
-	var yCandidates []int
-	for y := range y {
-		yCandidates = append(yCandidates, y)
+// forEach calls fn for every candidate position, in ascending x then y
+// order. Iteration stops early if fn returns false.
+func (s *candidateSet) forEach(fn func(x, y int) bool) {
+	for _, x := range s.xEdges {
+		for _, y := range s.yEdges {
+			if !fn(x, y) {
+				return
+			}
+		}
 	}
-
-	return xCandidates, yCandidates
 }
 
 // doRectanglesIntersect returns true if rectangles a and b intersect.
@@ -172,62 +724,252 @@ func doRectanglesIntersect(a, b placement) bool {
 	return true
 }
 
+// containsPlacement returns true if inner lies entirely within outer,
+// including when they share edges.
+func containsPlacement(outer, inner placement) bool {
+	return inner.x >= outer.x && inner.y >= outer.y &&
+		inner.x+inner.width <= outer.x+outer.width && inner.y+inner.height <= outer.y+outer.height
+}
+
 // hasIntersection checks if candidate intersects any rectangle in rects.
-func hasIntersection(candidate placement, placements []placement) bool {
-	for _, p := range placements {
+// The number of tests performed is recorded in stats.
+func hasIntersection(candidate placement, placements []placement, stats *PackStats) bool {
+	for i, p := range placements {
 		if doRectanglesIntersect(candidate, p) {
+			stats.countIntersectionTests(i + 1)
 			return true
 		}
 	}
+	stats.countIntersectionTests(len(placements))
 	return false
 }
 
-// findBestPlacement selects the candidate position that minimizes the overall bounding box area,
-// favoring positions whose center is closer to the center of the expanded bounding box.
-// The area and center are computed inline.
-func findBestPlacement(xCandidates, yCandidates []int, b bounds, r Rectangle, placements []placement) (int, int, bool) {
+// findBestPlacement selects the candidate position that minimizes the score of the overall bounding box,
+// resolving ties as configured by opts.TieBreak.
+// Candidates that would exceed the limits in opts or break the alignment are skipped.
+func findBestPlacement(b bounds, r Rectangle, placements []placement, candidates *candidateSet, c constraints, opts Options) (int, int, bool) {
 	// Allocate state for the heuristic.
 	var bestX, bestY int
-	var bestArea = math.MaxInt64
-	var bestCenterDistance = math.MaxInt64
+	var bestRank rank
 	var found = false
+	var obstacles = placements
+	if c.blocking != nil {
+		obstacles = c.blocking
+	}
 
-	// Evaluate all candidate positions.
-	for _, candidateX := range xCandidates {
-		for _, candidateY := range yCandidates {
-			var candidate = placement{
-				x:      candidateX,
-				y:      candidateY,
-				width:  r.Width,
-				height: r.Height,
-			}
+	// Evaluate a single candidate position.
+	var evaluated int
+	var consider = func(candidateX, candidateY int) {
+		if opts.MaxEvalPerRect > 0 && evaluated >= opts.MaxEvalPerRect {
+			return
+		}
+		evaluated++
+		opts.Stats.countCandidate()
+		candidateX, candidateY = opts.snap(candidateX), opts.snap(candidateY)
 
-			// If the candidate intersects any existing rectangle, skip it.
-			if hasIntersection(candidate, placements) {
-				continue
+		// If the candidate does not satisfy the alignment, or is not allowed, skip it.
+		if !c.align.allows(candidateX, candidateY) || !c.permits(candidateX, candidateY) {
+			return
+		}
+
+		var candidate = placement{
+			x:      candidateX,
+			y:      candidateY,
+			width:  r.Width,
+			height: r.Height,
+		}
+
+		candidateBB := expandBoundsForPlacement(candidate, b)
+		if len(placements) == 0 {
+			candidateBB = bounds{minX: candidate.x, minY: candidate.y, maxX: candidate.x + candidate.width, maxY: candidate.y + candidate.height}
+		}
+		// If the candidate grows the layout beyond the limits, skip it.
+		if !opts.fits(candidateBB) {
+			return
+		}
+
+		// Rank the candidate before the costlier checks, so that once a tight spot has been found,
+		// such as one that fills a hole without growing the layout, the worse candidates are skipped cheaply.
+		candidateRank := rankCandidate(candidate, b, candidateBB, c, opts)
+		if found && !candidateRank.less(bestRank) {
+			return
+		}
+
+		// If the candidate lies outside the regions, skip it.
+		if !opts.contains(candidate) {
+			return
+		}
+
+		// If the candidate overlaps the existing rectangles by more than allowed, skip it.
+		var overlap, ok = opts.overlap(candidate, obstacles)
+		if !ok && c.radii != nil && opts.MaxOverlapRatio <= 0 {
+			ok = c.clearOfCorners(candidate, obstacles)
+		}
+		if !ok {
+			return
+		}
+		candidateRank.overlap = overlap
+		if found && !candidateRank.less(bestRank) {
+			return
+		}
+		if opts.TieBreak == TieBreakLargestFree {
+			candidateRank.settleFree(candidate, candidateBB, obstacles)
+			if found && !candidateRank.less(bestRank) {
+				return
 			}
+		}
 
-			candidateBB := expandBoundsForPlacement(candidate, b)
-			// Inline area calculation.
-			candidateArea := (candidateBB.maxX - candidateBB.minX) * (candidateBB.maxY - candidateBB.minY)
-			// Inline center calculation.
-			bbCenterX := candidateBB.minX + (candidateBB.maxX-candidateBB.minX)/2
-			bbCenterY := candidateBB.minY + (candidateBB.maxY-candidateBB.minY)/2
-			candidateCenterX := candidate.x + candidate.width/2
-			candidateCenterY := candidate.y + candidate.height/2
-			dx := candidateCenterX - bbCenterX
-			dy := candidateCenterY - bbCenterY
-			centerDistance := dx*dx + dy*dy
+		bestRank = candidateRank
+		bestX = candidate.x
+		bestY = candidate.y
+		found = true
+	}
 
-			if candidateArea < bestArea || (candidateArea == bestArea && centerDistance < bestCenterDistance) {
-				bestArea = candidateArea
-				bestCenterDistance = centerDistance
-				bestX = candidate.x
-				bestY = candidate.y
-				found = true
+	// Evaluate all candidate positions. When overlap is allowed, also try tucking the rectangle
+	// back over its neighbours by as much as the ratio allows. When arranging rectangles around
+	// a center rectangle, also try placing them above and to the left of each edge.
+	if opts.Candidates != nil {
+		var existing = make([]Placement, len(placements))
+		for i, p := range placements {
+			existing[i] = Placement{Index: p.position, X: p.x, Y: p.y, Width: p.width, Height: p.height}
+		}
+		for _, point := range opts.Candidates(existing, r) {
+			if opts.MaxEvalPerRect > 0 && evaluated >= opts.MaxEvalPerRect {
+				break
 			}
+			consider(point.X, point.Y)
 		}
+		return bestX, bestY, found
+	}
+
+	var tuck = min(opts.MaxOverlapRatio, 1)
+	var tuckX, tuckY = int(tuck * float64(r.Width)), int(tuck * float64(r.Height))
+	var corner int
+	if tuck <= 0 {
+		corner = c.cornerTuck(obstacles)
 	}
+	candidates.forEach(func(candidateX, candidateY int) bool {
+		consider(candidateX, candidateY)
+		if corner > 0 {
+			consider(candidateX-corner, candidateY-corner)
+		}
+		if c.center != nil {
+			consider(candidateX-r.Width, candidateY)
+			consider(candidateX, candidateY-r.Height)
+			consider(candidateX-r.Width, candidateY-r.Height)
+		}
+		if tuckX > 0 {
+			consider(candidateX-tuckX, candidateY)
+		}
+		if tuckY > 0 {
+			consider(candidateX, candidateY-tuckY)
+		}
+		if tuckX > 0 && tuckY > 0 {
+			consider(candidateX-tuckX, candidateY-tuckY)
+		}
+		return opts.MaxEvalPerRect <= 0 || evaluated < opts.MaxEvalPerRect
+	})
 
 	return bestX, bestY, found
 }
+
+// rank orders candidate positions. Candidates are compared by penalty first,
+// then by whether they grow the bounding box, then by each score value, then
+// by the area they overlap other rectangles, and then by each tie-break value
+// in turn. Lower values are better.
+type rank struct {
+	penalty  int64
+	growth   int
+	score    [2]int64
+	overlap  int64
+	tieBreak [2]int64
+}
+
+// less returns true if r ranks better than o.
+func (r rank) less(o rank) bool {
+	if r.penalty != o.penalty {
+		return r.penalty < o.penalty
+	}
+	if r.growth != o.growth {
+		return r.growth < o.growth
+	}
+	if r.score[0] != o.score[0] {
+		return r.score[0] < o.score[0]
+	}
+	if r.score[1] != o.score[1] {
+		return r.score[1] < o.score[1]
+	}
+	if r.overlap != o.overlap {
+		return r.overlap < o.overlap
+	}
+	if r.tieBreak[0] != o.tieBreak[0] {
+		return r.tieBreak[0] < o.tieBreak[0]
+	}
+	return r.tieBreak[1] < o.tieBreak[1]
+}
+
+// settleFree replaces the placeholder left in the tie-break by rankCandidate
+// for TieBreakLargestFree with the negated area of the largest empty
+// rectangle within bb once candidate joins the placements.
+func (r *rank) settleFree(candidate placement, bb bounds, placements []placement) {
+	var free = -largestFree(bb, append(slices.Clip(placements), candidate))
+	for i := range r.tieBreak {
+		if r.tieBreak[i] == math.MinInt64 {
+			r.tieBreak[i] = free
+		}
+	}
+}
+
+// rankCandidate returns the rank of candidate given the bounding box b of the
+// existing placements and the bounding box bb that results from placing it.
+// Candidates that fit within b are always preferred over those that grow it,
+// unless growing is needed to satisfy the aspect ratio range. If c has a
+// center, candidates nearest to it are favored before applying the score. If c
+// has a hint, candidates nearest to it are favored before applying the
+// tie-break.
+func rankCandidate(candidate placement, b, bb bounds, c constraints, opts Options) rank {
+	var r = rank{
+		penalty: opts.aspectPenalty(bb),
+		score:   opts.Score.evaluate(candidate, bb),
+	}
+	if opts.AreaWeight != 0 || opts.DistanceWeight != 0 {
+		r.score = [2]int64{opts.weigh(candidate, bb)}
+	} else if opts.WidthBias != 0 && opts.Score == ScoreArea {
+		r.score = opts.biasedArea(bb)
+	}
+	if c.centrality > 0 {
+		// Weight the distance by the centrality itself, so the most important rectangles are pulled hardest.
+		r.score[0] += int64(c.centrality * float64(centerDistance(candidate, bb.center())))
+	}
+	if c.center != nil {
+		r.score = [2]int64{centerDistance(candidate, *c.center), r.score[0]}
+	}
+	if bb != b {
+		r.growth = 1
+	}
+	switch opts.TieBreak {
+	case TieBreakBottomLeft:
+		r.tieBreak = [2]int64{-int64(candidate.y + candidate.height), int64(candidate.x)}
+	case TieBreakTopLeft:
+		r.tieBreak = [2]int64{int64(candidate.y), int64(candidate.x)}
+	case TieBreakLargestFree:
+		// Start from the best possible value, so only candidates that could win pay for settleFree.
+		r.tieBreak = [2]int64{math.MinInt64, centerDistance(candidate, bb.center())}
+	default:
+		r.tieBreak[0] = centerDistance(candidate, bb.center())
+	}
+	if c.hint != nil {
+		var dx, dy = int64(candidate.x - c.hint.X), int64(candidate.y - c.hint.Y)
+		r.tieBreak = [2]int64{dx*dx + dy*dy, r.tieBreak[0]}
+	}
+	return r
+}
+
+// centerDistance returns the squared distance between the center of candidate
+// and center. The distance is computed in 64 bits so it does not overflow on
+// 32-bit platforms.
+func centerDistance(candidate placement, center image.Point) int64 {
+	var dx = int64(candidate.x + candidate.width/2 - center.X)
+	var dy = int64(candidate.y + candidate.height/2 - center.Y)
+	return dx*dx + dy*dy
+}
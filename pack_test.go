@@ -1,7 +1,12 @@
 package binpack_test
 
 import (
+	"image"
+	"math/rand"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/lewisgibson/go-binpack"
 	"github.com/stretchr/testify/require"
@@ -176,3 +181,1674 @@ func TestPack_TenRectangles(t *testing.T) {
 		}
 	}
 }
+
+// requireNoOverlap asserts that no two placements in the layout overlap.
+func requireNoOverlap(t *testing.T, layout binpack.Layout) {
+	t.Helper()
+	for i := 0; i < len(layout.Placements); i++ {
+		for j := i + 1; j < len(layout.Placements); j++ {
+			a, b := layout.Placements[i], layout.Placements[j]
+			require.False(t, rectanglesOverlapTest(
+				a.X, a.Y, a.Width, a.Height,
+				b.X, b.Y, b.Width, b.Height,
+			), "expected rectangle %d and %d not to overlap", a.Index, b.Index)
+		}
+	}
+}
+
+// TestPackWithOptions_Unbounded verifies that packing without limits matches Pack.
+func TestPackWithOptions_Unbounded(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a test packable with several rectangles.
+	rectangles := []binpack.Rectangle{
+		{Width: 100, Height: 200},
+		{Width: 50, Height: 50},
+		{Width: 80, Height: 120},
+	}
+	tp := newTestPackable(rectangles)
+
+	// Act: pack the rectangles without limits.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: every rectangle should be placed without overlap.
+	require.NoError(t, err)
+	require.Len(t, layout.Placements, len(rectangles))
+	requireNoOverlap(t, layout)
+
+	// Assert: the placements should be reported to the packable.
+	for _, p := range layout.Placements {
+		require.Equal(t, p.X, tp.placements[p.Index].x, "expected x-coordinate for rectangle %d", p.Index)
+		require.Equal(t, p.Y, tp.placements[p.Index].y, "expected y-coordinate for rectangle %d", p.Index)
+	}
+}
+
+// TestPackWithOptions_TooLarge verifies that ErrTooLarge is returned when the
+// rectangles do not fit within the limits.
+func TestPackWithOptions_TooLarge(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a test packable with rectangles that exceed the limits.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 100, Height: 100},
+		{Width: 100, Height: 100},
+	})
+
+	// Act: pack the rectangles into a space that only fits one.
+	_, err := binpack.PackWithOptions(tp, binpack.Options{MaxWidth: 150, MaxHeight: 150})
+
+	// Assert: the error should be ErrTooLarge.
+	require.ErrorIs(t, err, binpack.ErrTooLarge)
+}
+
+// TestPackBestEffort_Unplaced verifies that rectangles which do not fit are
+// reported as unplaced while the rest are packed within the limits.
+func TestPackBestEffort_Unplaced(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a test packable where not every rectangle fits.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 300, Height: 50},
+		{Width: 50, Height: 50},
+		{Width: 100, Height: 100},
+	})
+
+	// Act: pack the rectangles into a 100x150 space.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{MaxWidth: 100, MaxHeight: 150})
+
+	// Assert: the oversized rectangle should be unplaced.
+	require.Equal(t, []int{1}, unplaced)
+	require.Len(t, layout.Placements, 3)
+
+	// Assert: the layout should respect the limits and contain no overlaps.
+	require.LessOrEqual(t, layout.Width, 100)
+	require.LessOrEqual(t, layout.Height, 150)
+	requireNoOverlap(t, layout)
+}
+
+// TestPackBestEffort_AllFit verifies that no indices are returned when every
+// rectangle fits.
+func TestPackBestEffort_AllFit(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a test packable with rectangles that fit.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 50, Height: 50},
+	})
+
+	// Act: pack the rectangles into a generous space.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{MaxWidth: 100, MaxHeight: 100})
+
+	// Assert: every rectangle should be placed.
+	require.Empty(t, unplaced)
+	require.Len(t, layout.Placements, 2)
+}
+
+// TestPackWithOptions_ScorePerimeter verifies that minimizing the perimeter
+// produces a more square layout than minimizing the area.
+func TestPackWithOptions_ScorePerimeter(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles where the two scores disagree.
+	rectangles := []binpack.Rectangle{
+		{Width: 100, Height: 90},
+		{Width: 50, Height: 20},
+		{Width: 60, Height: 80},
+		{Width: 70, Height: 60},
+	}
+
+	// Act: pack the rectangles with each score.
+	area, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Score: binpack.ScoreArea})
+	require.NoError(t, err)
+	perimeter, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Score: binpack.ScorePerimeter})
+	require.NoError(t, err)
+
+	// Assert: the area score should produce the smaller area.
+	require.Less(t, area.Width*area.Height, perimeter.Width*perimeter.Height)
+
+	// Assert: the perimeter score should produce the smaller perimeter.
+	require.Less(t, perimeter.Width+perimeter.Height, area.Width+area.Height)
+
+	// Assert: both layouts should be free of overlaps.
+	requireNoOverlap(t, area)
+	requireNoOverlap(t, perimeter)
+}
+
+// TestRectangle_Scale verifies that scaled dimensions are rounded to the nearest integer.
+func TestRectangle_Scale(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a rectangle.
+	r := binpack.Rectangle{Width: 15, Height: 7}
+
+	// Act & Assert: scale by several factors.
+	require.Equal(t, binpack.Rectangle{Width: 30, Height: 14}, r.Scale(2))
+	require.Equal(t, binpack.Rectangle{Width: 23, Height: 11}, r.Scale(1.5))
+	require.Equal(t, binpack.Rectangle{Width: 5, Height: 2}, r.Scale(1.0/3))
+}
+
+// TestPackWithOptions_TieBreakLargestFree verifies that, among candidates of
+// equal score, the one leaving the largest empty rectangle is chosen.
+func TestPackWithOptions_TieBreakLargestFree(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tieBreak binpack.TieBreak
+		x        int
+	}{
+		{name: "Center", tieBreak: binpack.TieBreakCenter, x: 10},
+		{name: "LargestFree", tieBreak: binpack.TieBreakLargestFree, x: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: stack a bar under a block, then offer the square three spots under the bar.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 30, Height: 30},
+				{Width: 30, Height: 10},
+				{Width: 10, Height: 10},
+			})
+			candidates := func(placements []binpack.Placement, r binpack.Rectangle) []image.Point {
+				if r.Width == 30 {
+					return []image.Point{{X: 0, Y: 30}}
+				}
+				return []image.Point{{X: 0, Y: 40}, {X: 10, Y: 40}, {X: 20, Y: 40}}
+			}
+
+			// Act: pack with the tie break on the offered spots.
+			_, err := binpack.PackWithOptions(tp, binpack.Options{TieBreak: tt.tieBreak, Candidates: candidates})
+
+			// Assert: the middle spot is nearest the center but splits the free space.
+			require.NoError(t, err)
+			require.Equal(t, tt.x, tp.placements[2].x)
+			require.Equal(t, 40, tp.placements[2].y)
+		})
+	}
+}
+
+// TestRectangle_Contains verifies that points are tested against the
+// rectangle at the origin, including only its top-left edges.
+func TestRectangle_Contains(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a rectangle.
+	r := binpack.Rectangle{Width: 15, Height: 7}
+
+	// Act & Assert: test points inside, on the edges and outside.
+	require.True(t, r.Contains(0, 0))
+	require.True(t, r.Contains(14, 6))
+	require.False(t, r.Contains(15, 0))
+	require.False(t, r.Contains(0, 7))
+	require.False(t, r.Contains(-1, 3))
+	require.False(t, binpack.Rectangle{}.Contains(0, 0))
+}
+
+// TestPackWithOptions_TieBreak verifies that equal scoring candidates are
+// resolved according to the tie-break option.
+func TestPackWithOptions_TieBreak(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tieBreak binpack.TieBreak
+		x, y     int
+	}{
+		{name: "TopLeft", tieBreak: binpack.TieBreakTopLeft, x: 100, y: 0},
+		{name: "BottomLeft", tieBreak: binpack.TieBreakBottomLeft, x: 0, y: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create a block and a smaller square beside it.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 100, Height: 100},
+				{Width: 50, Height: 50},
+			})
+
+			// Act: pack the rectangles with the tie-break.
+			_, err := binpack.PackWithOptions(tp, binpack.Options{TieBreak: tt.tieBreak})
+
+			// Assert: the square should hug the expected corner.
+			require.NoError(t, err)
+			require.Equal(t, tt.x, tp.placements[1].x, "expected x-coordinate %d", tt.x)
+			require.Equal(t, tt.y, tp.placements[1].y, "expected y-coordinate %d", tt.y)
+		})
+	}
+}
+
+// weightedCenterDistance returns the sum of each placement's squared distance
+// from the center of the layout, weighted by the area of the placement.
+func weightedCenterDistance(layout binpack.Layout) int {
+	var total int
+	for _, p := range layout.Placements {
+		dx := p.X + p.Width/2 - layout.Width/2
+		dy := p.Y + p.Height/2 - layout.Height/2
+		total += p.Width * p.Height * (dx*dx + dy*dy)
+	}
+	return total
+}
+
+// TestPackWithOptions_ScoreWeightedCenter verifies that weighting the center
+// distance by area pulls large rectangles toward the center.
+func TestPackWithOptions_ScoreWeightedCenter(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles of varying sizes.
+	rectangles := []binpack.Rectangle{
+		{Width: 30, Height: 60},
+		{Width: 50, Height: 20},
+		{Width: 50, Height: 30},
+		{Width: 70, Height: 20},
+		{Width: 40, Height: 90},
+	}
+
+	// Act: pack the rectangles with and without weighting.
+	area, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Score: binpack.ScoreArea})
+	require.NoError(t, err)
+	weighted, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Score: binpack.ScoreWeightedCenter})
+	require.NoError(t, err)
+
+	// Assert: the weighted layout should be more balanced around its center.
+	require.Less(t, weightedCenterDistance(weighted), weightedCenterDistance(area))
+	requireNoOverlap(t, weighted)
+}
+
+// randomRectangles returns n rectangles with pseudo-random dimensions. The
+// same n always produces the same rectangles.
+func randomRectangles(n int) []binpack.Rectangle {
+	r := rand.New(rand.NewSource(int64(n)))
+	rectangles := make([]binpack.Rectangle, n)
+	for i := range rectangles {
+		rectangles[i] = binpack.Rectangle{Width: 1 + r.Intn(200), Height: 1 + r.Intn(200)}
+	}
+	return rectangles
+}
+
+// BenchmarkPack measures packing performance as the number of rectangles grows.
+func BenchmarkPack(b *testing.B) {
+	for _, n := range []int{10, 25, 50, 100} {
+		rectangles := randomRectangles(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				binpack.Pack(newTestPackable(rectangles))
+			}
+		})
+	}
+}
+
+// BenchmarkPack_Bounds measures maintaining the bounding box as rectangles
+// are placed. Each rectangle evaluates a single candidate, so the search no
+// longer hides the per-placement cost of the bookkeeping.
+func BenchmarkPack_Bounds(b *testing.B) {
+	for _, n := range []int{1000, 2000, 4000} {
+		rectangles := randomRectangles(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				binpack.PackBestEffort(newTestPackable(rectangles), binpack.Options{MaxEvalPerRect: 1})
+			}
+		})
+	}
+}
+
+// BenchmarkPack_UniformTiles measures packing equal tiles, which tile the
+// layout perfectly.
+func BenchmarkPack_UniformTiles(b *testing.B) {
+	rectangles := make([]binpack.Rectangle, 100)
+	for i := range rectangles {
+		rectangles[i] = binpack.Rectangle{Width: 32, Height: 32}
+	}
+	for i := 0; i < b.N; i++ {
+		binpack.Pack(newTestPackable(rectangles))
+	}
+}
+
+// TestPackBestEffort_Limit verifies that only the largest rectangles are packed
+// when a limit is set, and the rest are reported as unplaced.
+func TestPackBestEffort_Limit(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles of distinct sizes.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 40, Height: 40},
+		{Width: 20, Height: 20},
+		{Width: 30, Height: 30},
+	})
+
+	// Act: pack only the two largest rectangles.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{Limit: 2})
+
+	// Assert: the two smallest rectangles should be unplaced.
+	require.Equal(t, []int{0, 2}, unplaced)
+	require.Len(t, layout.Placements, 2)
+	for _, p := range layout.Placements {
+		require.Contains(t, []int{1, 3}, p.Index)
+	}
+}
+
+// TestPackWithOptions_Limit verifies that rectangles dropped by the limit are
+// not treated as an error.
+func TestPackWithOptions_Limit(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create more rectangles than the limit.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 20, Height: 20},
+		{Width: 30, Height: 30},
+	})
+
+	// Act: pack only the largest rectangle.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Limit: 1})
+
+	// Assert: only the largest rectangle should be placed.
+	require.NoError(t, err)
+	require.Equal(t, []binpack.Placement{{Index: 2, X: 0, Y: 0, Width: 30, Height: 30}}, layout.Placements)
+}
+
+// TestPack_ZeroAreaRectangles verifies that zero-area rectangles mixed with
+// real ones are placed at (0,0) without affecting the layout.
+func TestPack_ZeroAreaRectangles(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create real rectangles mixed with degenerate placeholders.
+	rectangles := []binpack.Rectangle{
+		{Width: 0, Height: 0},
+		{Width: 100, Height: 50},
+		{Width: 0, Height: 500},
+		{Width: 50, Height: 50},
+		{Width: 0, Height: 0},
+	}
+	tp := newTestPackable(rectangles)
+
+	// Act: pack the rectangles.
+	w, h := binpack.Pack(tp)
+
+	// Assert: the dimensions should only account for the real rectangles.
+	require.Equal(t, 150, w, "expected width 150")
+	require.Equal(t, 50, h, "expected height 50")
+
+	// Assert: the zero-area rectangles should be placed at (0, 0).
+	for _, i := range []int{0, 2, 4} {
+		require.Equal(t, 0, tp.placements[i].x, "expected x-coordinate 0 for rectangle %d", i)
+		require.Equal(t, 0, tp.placements[i].y, "expected y-coordinate 0 for rectangle %d", i)
+	}
+}
+
+// TestPack_OnlyZeroAreaRectangles verifies that a set of zero-area rectangles
+// packs into an empty layout.
+func TestPack_OnlyZeroAreaRectangles(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create only degenerate placeholders.
+	tp := newTestPackable([]binpack.Rectangle{{}, {}})
+
+	// Act: pack the rectangles.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: every rectangle should be placed at (0, 0) in an empty layout.
+	require.NoError(t, err)
+	require.Equal(t, 0, layout.Width, "expected width 0")
+	require.Equal(t, 0, layout.Height, "expected height 0")
+	require.Len(t, layout.Placements, 2)
+}
+
+// TestPackWithOptions_AspectRange verifies that the layout is kept within the
+// aspect ratio range.
+func TestPackWithOptions_AspectRange(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles that would otherwise form a long strip.
+	rectangles := make([]binpack.Rectangle, 8)
+	for i := range rectangles {
+		rectangles[i] = binpack.Rectangle{Width: 40, Height: 10}
+	}
+
+	tests := []struct {
+		name                 string
+		minAspect, maxAspect float64
+	}{
+		{name: "Landscape", minAspect: 1.5, maxAspect: 2},
+		{name: "Portrait", minAspect: 0.5, maxAspect: 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack the rectangles within the range.
+			layout, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{
+				MinAspect: tt.minAspect,
+				MaxAspect: tt.maxAspect,
+			})
+
+			// Assert: the layout should be within the range.
+			require.NoError(t, err)
+			aspect := float64(layout.Width) / float64(layout.Height)
+			require.GreaterOrEqual(t, aspect, tt.minAspect)
+			require.LessOrEqual(t, aspect, tt.maxAspect)
+			requireNoOverlap(t, layout)
+		})
+	}
+}
+
+// TestPackWithOptions_AspectRangeImpossible verifies that ErrAspectRatio is
+// returned when the range cannot be satisfied.
+func TestPackWithOptions_AspectRangeImpossible(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a single rectangle that is too wide for the range.
+	tp := newTestPackable([]binpack.Rectangle{{Width: 100, Height: 10}})
+
+	// Act: pack the rectangle.
+	_, err := binpack.PackWithOptions(tp, binpack.Options{MaxAspect: 2})
+
+	// Assert: the error should be ErrAspectRatio.
+	require.ErrorIs(t, err, binpack.ErrAspectRatio)
+}
+
+// TestPackWithOptions_PrefersNoGrowth verifies that a small rectangle tucks
+// into an existing gap rather than extending the layout.
+func TestPackWithOptions_PrefersNoGrowth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		score binpack.Score
+	}{
+		{name: "Area", score: binpack.ScoreArea},
+		{name: "Perimeter", score: binpack.ScorePerimeter},
+		{name: "WeightedCenter", score: binpack.ScoreWeightedCenter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create a block and a square that leave a gap beside the block.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 100, Height: 100},
+				{Width: 50, Height: 50},
+				{Width: 40, Height: 40},
+			})
+
+			// Act: pack the rectangles.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{Score: tt.score})
+
+			// Assert: the smallest rectangle should not extend the layout.
+			require.NoError(t, err)
+			require.Equal(t, 150*100, layout.Width*layout.Height, "expected a 150x100 layout")
+			require.Equal(t, 150, max(layout.Width, layout.Height), "expected a 150x100 layout")
+			requireNoOverlap(t, layout)
+		})
+	}
+}
+
+// TestPackWithOptions_Hints verifies that equally scoring candidates are
+// resolved toward the hinted position.
+func TestPackWithOptions_Hints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hint image.Point
+		want image.Point
+	}{
+		{name: "Right", hint: image.Pt(90, 10), want: image.Pt(100, 0)},
+		{name: "Below", hint: image.Pt(10, 90), want: image.Pt(0, 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create a block and a square with several equally scoring positions.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 100, Height: 100},
+				{Width: 50, Height: 50},
+			})
+
+			// Act: pack the rectangles with a hint for the square.
+			_, err := binpack.PackWithOptions(tp, binpack.Options{Hints: []image.Point{{}, tt.hint}})
+
+			// Assert: the square should be placed at the candidate nearest the hint.
+			require.NoError(t, err)
+			require.Equal(t, tt.want.X, tp.placements[1].x, "expected x-coordinate %d", tt.want.X)
+			require.Equal(t, tt.want.Y, tp.placements[1].y, "expected y-coordinate %d", tt.want.Y)
+		})
+	}
+}
+
+// TestPack_LargeDimensions verifies that scoring does not overflow when the
+// layout area exceeds 32 bits, by checking that scaling every rectangle up
+// scales the layout by the same factor.
+func TestPack_LargeDimensions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles and a copy scaled beyond 32-bit areas.
+	rectangles := []binpack.Rectangle{
+		{Width: 60, Height: 20},
+		{Width: 40, Height: 40},
+		{Width: 20, Height: 60},
+		{Width: 30, Height: 10},
+		{Width: 10, Height: 50},
+	}
+	scaled := make([]binpack.Rectangle, len(rectangles))
+	for i, r := range rectangles {
+		scaled[i] = r.Scale(1000)
+	}
+
+	// Act: pack both sets of rectangles.
+	w, h := binpack.Pack(newTestPackable(rectangles))
+	scaledW, scaledH := binpack.Pack(newTestPackable(scaled))
+
+	// Assert: the scaled layout should have the scaled dimensions.
+	require.Equal(t, w*1000, scaledW, "expected width %d", w*1000)
+	require.Equal(t, h*1000, scaledH, "expected height %d", h*1000)
+}
+
+// TestPackWithOptions_Snap verifies that every rectangle is snapped to the
+// grid and that the layout dimensions are rounded up to it, whichever
+// rounding is used.
+func TestPackWithOptions_Snap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		rounding binpack.GridRounding
+	}{
+		{name: "Up", rounding: binpack.RoundUp},
+		{name: "Down", rounding: binpack.RoundDown},
+		{name: "Nearest", rounding: binpack.RoundNearest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create rectangles whose sizes are not multiples of the grid.
+			tp := newTestPackable(randomRectangles(20))
+
+			// Act: pack the rectangles onto an 8 pixel grid.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{Grid: 8, GridRounding: tt.rounding})
+
+			// Assert: the positions and dimensions should be multiples of the grid.
+			require.NoError(t, err)
+			require.Zero(t, layout.Width%8, "expected width %d to be a multiple of 8", layout.Width)
+			require.Zero(t, layout.Height%8, "expected height %d to be a multiple of 8", layout.Height)
+			for _, placement := range layout.Placements {
+				require.Zero(t, placement.X%8, "expected x-coordinate %d to be a multiple of 8", placement.X)
+				require.Zero(t, placement.Y%8, "expected y-coordinate %d to be a multiple of 8", placement.Y)
+				require.LessOrEqual(t, placement.X+placement.Width, layout.Width)
+				require.LessOrEqual(t, placement.Y+placement.Height, layout.Height)
+			}
+			requireNoOverlap(t, layout)
+		})
+	}
+}
+
+// TestPackWithOptions_SnapLimits verifies that the rounded dimensions are
+// checked against the limits.
+func TestPackWithOptions_SnapLimits(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a rectangle that fits the limits until it is rounded up.
+	tp := newTestPackable([]binpack.Rectangle{{Width: 30, Height: 30}})
+
+	// Act: pack the rectangle onto a 16 pixel grid.
+	_, err := binpack.PackWithOptions(tp, binpack.Options{MaxWidth: 30, MaxHeight: 30, Grid: 16})
+
+	// Assert: the rounded layout should be too large.
+	require.ErrorIs(t, err, binpack.ErrTooLarge)
+}
+
+// TestPackBestEffort_Regions verifies that rectangles are only placed within
+// the union of the regions.
+func TestPackBestEffort_Regions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles that exactly fill an L-shaped canvas, and one more.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 100, Height: 50},
+		{Width: 30, Height: 100},
+		{Width: 40, Height: 40},
+	})
+	regions := []image.Rectangle{
+		image.Rect(0, 0, 100, 50),
+		image.Rect(0, 50, 30, 150),
+	}
+
+	// Act: pack the rectangles into the canvas.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{Regions: regions})
+
+	// Assert: the canvas should be filled and the extra rectangle left out.
+	require.Equal(t, []int{2}, unplaced)
+	require.Equal(t, 100, layout.Width)
+	require.Equal(t, 150, layout.Height)
+	require.Equal(t, 0, tp.placements[0].x)
+	require.Equal(t, 0, tp.placements[0].y)
+	require.Equal(t, 0, tp.placements[1].x)
+	require.Equal(t, 50, tp.placements[1].y)
+}
+
+// TestPackWithOptions_RegionOffset verifies that positions are reported in
+// canvas coordinates when the regions do not start at the origin.
+func TestPackWithOptions_RegionOffset(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a rectangle that fills an offset region.
+	tp := newTestPackable([]binpack.Rectangle{{Width: 50, Height: 50}})
+
+	// Act: pack the rectangle into the region.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Regions: []image.Rectangle{image.Rect(10, 20, 60, 70)}})
+
+	// Assert: the rectangle should be placed at the corner of the region.
+	require.NoError(t, err)
+	require.Equal(t, 10, tp.placements[0].x)
+	require.Equal(t, 20, tp.placements[0].y)
+	require.Equal(t, 60, layout.Width)
+	require.Equal(t, 70, layout.Height)
+}
+
+// TestPackWithOptions_LessIndex verifies that rectangles are placed in the
+// order given by LessIndex.
+func TestPackWithOptions_LessIndex(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create rectangles and a priority held outside the Packable.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 50, Height: 50},
+		{Width: 30, Height: 30},
+	})
+	priority := []int{0, 2, 1}
+
+	// Act: pack only the two rectangles with the highest priority.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{
+		Limit: 2,
+		LessIndex: func(i, j int) bool {
+			return priority[i] < priority[j]
+		},
+	})
+
+	// Assert: the first rectangle should be placed first, and the largest left out.
+	require.Equal(t, []int{1}, unplaced)
+	require.Len(t, layout.Placements, 2)
+	require.Equal(t, 0, layout.Placements[0].Index)
+	require.Equal(t, 2, layout.Placements[1].Index)
+}
+
+// TestPack_UniformTiles verifies that equal tiles are packed without any
+// wasted space.
+func TestPack_UniformTiles(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create equal tiles.
+	rectangles := make([]binpack.Rectangle, 16)
+	for i := range rectangles {
+		rectangles[i] = binpack.Rectangle{Width: 32, Height: 32}
+	}
+	tp := newTestPackable(rectangles)
+
+	// Act: pack the tiles.
+	w, h := binpack.Pack(tp)
+
+	// Assert: the layout should be exactly the area of the tiles.
+	require.Equal(t, 16*32*32, w*h)
+}
+
+// TestPack_TieBreakBaseline verifies that every candidate that fills the
+// bounding box is still compared on its tie-break, so that a set that tiles
+// its bounding box early keeps its compact layout.
+func TestPack_TieBreakBaseline(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: rectangles whose first placements fill their bounding box exactly.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 16, Height: 1},
+		{Width: 16, Height: 1},
+		{Width: 11, Height: 1},
+		{Width: 21, Height: 1},
+		{Width: 6, Height: 26},
+		{Width: 6, Height: 26},
+		{Width: 21, Height: 6},
+		{Width: 11, Height: 6},
+	})
+
+	// Act: pack the rectangles with the default options.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: the layout should match the one found by comparing every candidate.
+	require.NoError(t, err)
+	require.Equal(t, 23, layout.Width)
+	require.Equal(t, 35, layout.Height)
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_OrderBy verifies that the placements are listed in the
+// requested order.
+func TestPackWithOptions_OrderBy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		order binpack.Order
+		want  []int
+	}{
+		{name: "Input", order: binpack.OrderInput, want: []int{0, 1, 2}},
+		{name: "Placement", order: binpack.OrderPlacement, want: []int{1, 2, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create rectangles that are placed in a different order to their indices.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 10, Height: 10},
+				{Width: 30, Height: 30},
+				{Width: 20, Height: 20},
+			})
+
+			// Act: pack the rectangles.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{OrderBy: tt.order})
+
+			// Assert: the placements should be listed in the requested order.
+			require.NoError(t, err)
+			indices := make([]int, len(layout.Placements))
+			for i, placement := range layout.Placements {
+				indices[i] = placement.Index
+			}
+			require.Equal(t, tt.want, indices)
+		})
+	}
+}
+
+// TestPack_Deterministic verifies that packing the same rectangles always
+// produces the same layout.
+func TestPack_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pack a set of random rectangles once as a reference.
+	rectangles := randomRectangles(60)
+	want, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		// Act: pack the rectangles again.
+		got, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+
+		// Assert: the layout should be identical.
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
+
+// TestPack_CandidateAllocations verifies that the candidate positions are
+// maintained as rectangles are placed rather than rebuilt for each one, which
+// would allocate for every rectangle.
+func TestPack_CandidateAllocations(t *testing.T) {
+	// Not parallel: allocations are counted across the whole process.
+
+	// Arrange: a hundred random rectangles.
+	rectangles := randomRectangles(100)
+
+	// Act: count the allocations of packing them.
+	allocs := testing.AllocsPerRun(2, func() {
+		binpack.Pack(newTestPackable(rectangles))
+	})
+
+	// Assert: fewer allocations than rectangles.
+	require.Less(t, allocs, float64(len(rectangles)))
+}
+
+// BenchmarkPack_Candidates measures maintaining the candidate positions as
+// the number of rectangles, and so of edges, grows large.
+func BenchmarkPack_Candidates(b *testing.B) {
+	for _, n := range []int{100, 200, 400} {
+		rectangles := randomRectangles(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				binpack.Pack(newTestPackable(rectangles))
+			}
+		})
+	}
+}
+
+// TestPackWithOptions_ScoreMaxSide verifies that ScoreMaxSide minimizes the
+// larger side of the layout even when that costs area.
+func TestPackWithOptions_ScoreMaxSide(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		score         binpack.Score
+		width, height int
+	}{
+		{name: "Area", score: binpack.ScoreArea, width: 160, height: 40},
+		{name: "MaxSide", score: binpack.ScoreMaxSide, width: 100, height: 80},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create rectangles that are smaller side by side but squarer stacked.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 100, Height: 40},
+				{Width: 60, Height: 40},
+			})
+
+			// Act: pack the rectangles with the score.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{Score: tt.score})
+
+			// Assert: the layout should have the expected dimensions.
+			require.NoError(t, err)
+			require.Equal(t, tt.width, layout.Width)
+			require.Equal(t, tt.height, layout.Height)
+		})
+	}
+}
+
+// TestPackWithOptions_MaxOverlapRatio verifies that rectangles may overlap by
+// up to the ratio to reduce the layout, and that the overlaps are reported.
+func TestPackWithOptions_MaxOverlapRatio(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		ratio    float64
+		area     int
+		overlaps []binpack.OverlapPair
+	}{
+		{name: "Strict", ratio: 0, area: 200 * 100},
+		{name: "Tolerant", ratio: 0.1, area: 190 * 100, overlaps: []binpack.OverlapPair{{A: 0, B: 1, Area: 1000}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create two equal squares.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 100, Height: 100},
+				{Width: 100, Height: 100},
+			})
+
+			// Act: pack the squares with the overlap ratio.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{MaxOverlapRatio: tt.ratio})
+
+			// Assert: the squares should overlap by no more than the ratio.
+			require.NoError(t, err)
+			require.Equal(t, tt.area, layout.Width*layout.Height)
+			require.Equal(t, tt.overlaps, layout.Overlaps)
+		})
+	}
+}
+
+// TestPackWithOptions_EdgeMargin verifies that rectangles are kept clear of
+// the edges of the layout, and that the margin counts towards the limits.
+func TestPackWithOptions_EdgeMargin(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		heuristic binpack.Heuristic
+	}{
+		{name: "Default", heuristic: binpack.HeuristicDefault},
+		{name: "Grid", heuristic: binpack.HeuristicGrid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create two squares that fit side by side only without the margin.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 50, Height: 50},
+				{Width: 50, Height: 50},
+			})
+
+			// Act: pack the squares with a margin, limiting the width.
+			layout, err := binpack.PackWithOptions(tp, binpack.Options{Heuristic: tt.heuristic, Columns: 1, EdgeMargin: 5, MaxWidth: 100})
+
+			// Assert: the squares should be stacked within the margin.
+			require.NoError(t, err)
+			require.Equal(t, 60, layout.Width)
+			require.Equal(t, 110, layout.Height)
+			for _, placement := range layout.Placements {
+				require.Equal(t, 5, placement.X)
+				require.GreaterOrEqual(t, placement.Y, 5)
+				require.LessOrEqual(t, placement.Y+placement.Height, layout.Height-5)
+			}
+		})
+	}
+}
+
+// TestSortByArea verifies that rectangles are sorted in the order Pack places
+// them.
+func TestSortByArea(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pack a set of random rectangles, listing the placements in placement order.
+	rectangles := randomRectangles(30)
+	layout, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{OrderBy: binpack.OrderPlacement})
+	require.NoError(t, err)
+
+	// Act: sort a copy of the rectangles.
+	sorted := slices.Clone(rectangles)
+	binpack.SortByArea(sorted)
+
+	// Assert: the sorted rectangles should match the placement order.
+	for i, placement := range layout.Placements {
+		require.Equal(t, rectangles[placement.Index], sorted[i])
+	}
+}
+
+// TestPackWithOptions_CenterIndex verifies that the other rectangles are
+// arranged on every side of the center rectangle, and that the option is only
+// used when enabled.
+func TestPackWithOptions_CenterIndex(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: twelve tiles can ring a rectangle twice their size.
+	rectangles := make([]binpack.Rectangle, 13)
+	for i := range rectangles {
+		rectangles[i] = binpack.Rectangle{Width: 10, Height: 10}
+	}
+	rectangles[3] = binpack.Rectangle{Width: 20, Height: 20}
+	tests := []struct {
+		name     string
+		opts     binpack.Options
+		centered bool
+	}{
+		{name: "Centered", opts: binpack.Options{Centered: true, CenterIndex: 3}, centered: true},
+		{name: "NotCentered", opts: binpack.Options{CenterIndex: 3}},
+		{name: "Negative", opts: binpack.Options{Centered: true, CenterIndex: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack with the center options.
+			layout, err := binpack.PackWithOptions(newTestPackable(rectangles), tt.opts)
+
+			// Assert: only the enabled option surrounds the center rectangle.
+			require.NoError(t, err)
+			requireNoOverlap(t, layout)
+			hero := layout.Placements[3]
+			require.Equal(t, tt.centered, hero.X == 10 && hero.Y == 10 && layout.Width == 40 && layout.Height == 40)
+		})
+	}
+}
+
+// TestPackWithOptions_RelativeTo verifies that positions are translated so
+// that the anchor rectangle is at the origin, and only when enabled.
+func TestPackWithOptions_RelativeTo(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: ring a large rectangle with tiles, so some lie above and left of it.
+	rectangles := make([]binpack.Rectangle, 13)
+	for i := range rectangles {
+		rectangles[i] = binpack.Rectangle{Width: 10, Height: 10}
+	}
+	rectangles[3] = binpack.Rectangle{Width: 20, Height: 20}
+	opts := binpack.Options{Centered: true, CenterIndex: 3}
+	base, err := binpack.PackWithOptions(newTestPackable(rectangles), opts)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		opts   binpack.Options
+		dx, dy int
+	}{
+		{name: "Relative", opts: binpack.Options{Centered: true, CenterIndex: 3, Relative: true, RelativeTo: 3}, dx: 10, dy: 10},
+		{name: "Disabled", opts: binpack.Options{Centered: true, CenterIndex: 3, RelativeTo: 3}},
+		{name: "Unknown", opts: binpack.Options{Centered: true, CenterIndex: 3, Relative: true, RelativeTo: 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack relative to the anchor.
+			tp := newTestPackable(rectangles)
+			layout, err := binpack.PackWithOptions(tp, tt.opts)
+
+			// Assert: every placement is moved by the position of the anchor.
+			require.NoError(t, err)
+			require.Equal(t, base.Width, layout.Width)
+			require.Equal(t, base.Height, layout.Height)
+			for i, p := range layout.Placements {
+				require.Equal(t, base.Placements[i].X-tt.dx, p.X)
+				require.Equal(t, base.Placements[i].Y-tt.dy, p.Y)
+				require.Equal(t, p.X, tp.placements[p.Index].x)
+				require.Equal(t, p.Y, tp.placements[p.Index].y)
+			}
+		})
+	}
+}
+
+// TestPackBestEffort_StopWhen verifies that packing ends once StopWhen is
+// satisfied and that the remaining rectangles are reported as unplaced.
+func TestPackBestEffort_StopWhen(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: stop once three rectangles have been placed.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 40, Height: 40},
+		{Width: 20, Height: 20},
+		{Width: 30, Height: 30},
+		{Width: 5, Height: 5},
+	})
+	var calls int
+	opts := binpack.Options{StopWhen: func(partial binpack.Layout) bool {
+		calls++
+		return len(partial.Placements) >= 3
+	}}
+
+	// Act: pack with the stop condition.
+	layout, unplaced := binpack.PackBestEffort(tp, opts)
+
+	// Assert: the three largest rectangles are kept and the rest are left unplaced.
+	require.Equal(t, 3, calls)
+	require.Len(t, layout.Placements, 3)
+	require.Equal(t, []int{0, 4}, unplaced)
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_CanonicalOrder verifies that the same sizes in a
+// different order are given the same positions.
+func TestPackWithOptions_CanonicalOrder(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: include rectangles of equal area but different shapes, and shuffle a copy.
+	rectangles := append(randomRectangles(30),
+		binpack.Rectangle{Width: 4, Height: 36},
+		binpack.Rectangle{Width: 36, Height: 4},
+		binpack.Rectangle{Width: 12, Height: 12},
+		binpack.Rectangle{Width: 12, Height: 12},
+	)
+	shuffled := slices.Clone(rectangles)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	// Act: pack both orders canonically.
+	first, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{CanonicalOrder: true})
+	require.NoError(t, err)
+	second, err := binpack.PackWithOptions(newTestPackable(shuffled), binpack.Options{CanonicalOrder: true})
+	require.NoError(t, err)
+
+	// Assert: the layouts match once the indices are set aside.
+	positions := func(layout binpack.Layout) []binpack.Placement {
+		var placements []binpack.Placement
+		for _, p := range layout.Placements {
+			p.Index = 0
+			placements = append(placements, p)
+		}
+		slices.SortFunc(placements, func(a, b binpack.Placement) int {
+			if a.X != b.X {
+				return a.X - b.X
+			}
+			return a.Y - b.Y
+		})
+		return placements
+	}
+	require.Equal(t, first.Width, second.Width)
+	require.Equal(t, first.Height, second.Height)
+	require.Equal(t, positions(first), positions(second))
+}
+
+// TestPackWithOptions_YUp verifies that positions are flipped to grow upward
+// from the bottom of the layout.
+func TestPackWithOptions_YUp(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: fifteen random rectangles and their layout measured downward.
+	rectangles := randomRectangles(15)
+	down, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+
+	// Act: pack them again with the y-axis pointing up.
+	up, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{YUp: true})
+
+	// Assert: every rectangle keeps its place but is measured from the bottom.
+	require.NoError(t, err)
+	require.Equal(t, down.Width, up.Width)
+	require.Equal(t, down.Height, up.Height)
+	for i, p := range up.Placements {
+		require.Equal(t, down.Placements[i].X, p.X)
+		require.Equal(t, down.Height-down.Placements[i].Y-p.Height, p.Y)
+	}
+}
+
+// TestPackBestEffort_ShelfSweep verifies that a rectangle the capped candidate
+// search cannot place is still put in a free slot of a bounded layout.
+func TestPackBestEffort_ShelfSweep(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: the only candidate evaluated for each tile is the occupied top-left corner.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+	})
+	opts := binpack.Options{MaxWidth: 20, MaxHeight: 20, MaxEvalPerRect: 1}
+
+	// Act: pack with a single candidate per rectangle.
+	layout, unplaced := binpack.PackBestEffort(tp, opts)
+
+	// Assert: the sweep fills the bin.
+	require.Empty(t, unplaced)
+	require.Equal(t, 20, layout.Width)
+	require.Equal(t, 20, layout.Height)
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_Strict verifies that strict mode reports the rectangles
+// the capped candidate search cannot place instead of falling back.
+func TestPackWithOptions_Strict(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+	}{
+		{name: "Bounded", opts: binpack.Options{MaxWidth: 20, MaxHeight: 20, MaxEvalPerRect: 1, Strict: true}},
+		{name: "Unbounded", opts: binpack.Options{MaxEvalPerRect: 1, Strict: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: the only candidate evaluated for each tile is the occupied top-left corner.
+			tp := newTestPackable([]binpack.Rectangle{
+				{Width: 10, Height: 10},
+				{Width: 10, Height: 10},
+				{Width: 10, Height: 10},
+			})
+
+			// Act: pack with the capped, strict search.
+			_, err := binpack.PackWithOptions(tp, tt.opts)
+
+			// Assert: the rectangles without a candidate are reported.
+			require.ErrorIs(t, err, binpack.ErrNoCandidate)
+			require.ErrorContains(t, err, "rectangles [1 2]")
+		})
+	}
+}
+
+// TestPackWithOptions_Quantize verifies that positions are rounded to the
+// quantum without introducing overlaps.
+func TestPackWithOptions_Quantize(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: forty random rectangles.
+	rectangles := randomRectangles(40)
+
+	// Act: pack with a quantum of 16.
+	layout, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Quantize: 16})
+
+	// Assert: every position is a multiple of the quantum and the rectangles do not overlap.
+	require.NoError(t, err)
+	for _, p := range layout.Placements {
+		require.Zero(t, p.X%16, "rectangle %d is at x=%d", p.Index, p.X)
+		require.Zero(t, p.Y%16, "rectangle %d is at y=%d", p.Index, p.Y)
+		require.LessOrEqual(t, p.X+p.Width, layout.Width)
+		require.LessOrEqual(t, p.Y+p.Height, layout.Height)
+	}
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_QuantizeEdgeMargin verifies that positions are rounded
+// to multiples of the quantum measured from the edge margin.
+func TestPackWithOptions_QuantizeEdgeMargin(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: twenty random rectangles.
+	rectangles := randomRectangles(20)
+
+	// Act: pack with an edge margin and a quantum of 10.
+	layout, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{EdgeMargin: 3, Quantize: 10})
+
+	// Assert: every rectangle should be inside the margin on a multiple measured from it.
+	require.NoError(t, err)
+	for _, p := range layout.Placements {
+		require.GreaterOrEqual(t, p.X, 3, "rectangle %d is at x=%d", p.Index, p.X)
+		require.GreaterOrEqual(t, p.Y, 3, "rectangle %d is at y=%d", p.Index, p.Y)
+		require.Zero(t, (p.X-3)%10, "rectangle %d is at x=%d", p.Index, p.X)
+		require.Zero(t, (p.Y-3)%10, "rectangle %d is at y=%d", p.Index, p.Y)
+		require.LessOrEqual(t, p.X+p.Width+3, layout.Width)
+		require.LessOrEqual(t, p.Y+p.Height+3, layout.Height)
+	}
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_QuantizeRegions verifies that positions are rounded to
+// multiples of the quantum measured from the region origin and stay within
+// the region.
+func TestPackWithOptions_QuantizeRegions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: four small rectangles and a region offset from the origin.
+	rectangles := []binpack.Rectangle{
+		{Width: 7, Height: 7},
+		{Width: 7, Height: 7},
+		{Width: 7, Height: 7},
+		{Width: 7, Height: 7},
+	}
+	region := image.Rect(3, 3, 103, 103)
+
+	// Act: pack into the region with a quantum of 10.
+	layout, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Regions: []image.Rectangle{region}, Quantize: 10})
+
+	// Assert: every rectangle is inside the region on a multiple measured from its origin.
+	require.NoError(t, err)
+	for _, p := range layout.Placements {
+		require.True(t, image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height).In(region), "rectangle %d is at %d,%d", p.Index, p.X, p.Y)
+		require.Zero(t, (p.X-3)%10, "rectangle %d is at x=%d", p.Index, p.X)
+		require.Zero(t, (p.Y-3)%10, "rectangle %d is at y=%d", p.Index, p.Y)
+	}
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_QuantizePin verifies that pinned rectangles keep their
+// positions when the others are rounded.
+func TestPackWithOptions_QuantizePin(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: the first rectangle is pinned off the quantum.
+	tp := &testPinPackable{testPackable: newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+	}), pins: map[int]image.Point{0: {X: 5, Y: 5}}}
+
+	// Act: pack with a quantum of 8, keeping the pin.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Quantize: 8, KeepPinPositions: true})
+
+	// Assert: the pinned rectangle keeps its position and the others are rounded.
+	require.NoError(t, err)
+	for _, p := range layout.Placements {
+		if p.Index == 0 {
+			require.Equal(t, image.Pt(5, 5), image.Pt(p.X, p.Y))
+			continue
+		}
+		require.Zero(t, p.X%8, "rectangle %d is at x=%d", p.Index, p.X)
+		require.Zero(t, p.Y%8, "rectangle %d is at y=%d", p.Index, p.Y)
+	}
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_AlignTo verifies that the dimensions are rounded up to
+// the alignment, which counts towards the limits.
+func TestPackWithOptions_AlignTo(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: two rectangles that line up in a 17x10 layout.
+	rectangles := []binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 7, Height: 5},
+	}
+
+	// Act: pack them aligned to 4, and the first alone against a limit it rounds past.
+	layout, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{AlignTo: 4, MaxHeight: 12})
+	_, tooLarge := binpack.PackWithOptions(newTestPackable(rectangles[:1]), binpack.Options{AlignTo: 4, MaxWidth: 10})
+
+	// Assert: the rectangles keep their positions in a 17x10 layout rounded up to 20x12.
+	require.NoError(t, err)
+	require.Equal(t, 20, layout.Width)
+	require.Equal(t, 12, layout.Height)
+	require.Equal(t, binpack.Placement{Index: 1, X: 10, Y: 0, Width: 7, Height: 5}, layout.Placements[1])
+	require.ErrorIs(t, tooLarge, binpack.ErrTooLarge)
+}
+
+// TestPackWithOptions_WidthBias verifies that a positive bias grows the layout
+// sideways and a negative one downward.
+func TestPackWithOptions_WidthBias(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: thirty random rectangles.
+	rectangles := randomRectangles(30)
+
+	// Act: pack them with no bias, leaning each way, and past the limit.
+	neutral, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+	wide, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{WidthBias: 0.5})
+	require.NoError(t, err)
+	tall, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{WidthBias: -0.5})
+	require.NoError(t, err)
+	clamped, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{WidthBias: 5})
+	require.NoError(t, err)
+	widest, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{WidthBias: 1})
+	require.NoError(t, err)
+
+	// Assert: the bias changes the aspect ratio and is clamped to one.
+	require.Greater(t, float64(wide.Width)/float64(wide.Height), float64(neutral.Width)/float64(neutral.Height))
+	require.Less(t, float64(tall.Width)/float64(tall.Height), float64(neutral.Width)/float64(neutral.Height))
+	require.Equal(t, widest, clamped)
+	requireNoOverlap(t, wide)
+	requireNoOverlap(t, tall)
+}
+
+// TestPackWithOptions_Candidates verifies that only the positions supplied by
+// the Candidates callback are evaluated.
+func TestPackWithOptions_Candidates(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: snap every tile to guide lines 25 pixels apart.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 20, Height: 20},
+		{Width: 20, Height: 20},
+		{Width: 20, Height: 20},
+		{Width: 20, Height: 20},
+	})
+	guides := func(placements []binpack.Placement, r binpack.Rectangle) []image.Point {
+		var points []image.Point
+		for y := 0; y <= 100; y += 25 {
+			for x := 0; x <= 100; x += 25 {
+				points = append(points, image.Pt(x, y))
+			}
+		}
+		return points
+	}
+
+	// Act: pack on the guide points.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Candidates: guides})
+
+	// Assert: the tiles should sit on the guides, leaving gaps between them.
+	require.NoError(t, err)
+	require.Equal(t, 95, layout.Width)
+	require.Equal(t, 20, layout.Height)
+	for _, p := range layout.Placements {
+		require.Zero(t, p.X%25)
+		require.Zero(t, p.Y%25)
+	}
+	requireNoOverlap(t, layout)
+}
+
+// TestPackWithOptions_PartialError verifies that the error carries the layout
+// of the rectangles that could be placed, which are not applied.
+func TestPackWithOptions_PartialError(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: only two of the three tiles fit.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+		{Width: 10, Height: 10},
+	})
+	tp.placements[0].x = -1
+
+	// Act: pack into a bin too small for all three.
+	_, err := binpack.PackWithOptions(tp, binpack.Options{MaxWidth: 20, MaxHeight: 10})
+
+	// Assert: the error carries the partial layout and the Packable is untouched.
+	require.ErrorIs(t, err, binpack.ErrTooLarge)
+	var partial binpack.PartialError
+	require.ErrorAs(t, err, &partial)
+	require.Len(t, partial.Partial().Placements, 2)
+	require.Equal(t, 20, partial.Partial().Width)
+	require.Equal(t, -1, tp.placements[0].x)
+}
+
+// TestPackBestEffort_NeverOverlaps packs many random inputs under a mix of
+// options and verifies that no two rectangles ever overlap.
+func TestPackBestEffort_NeverOverlaps(t *testing.T) {
+	t.Parallel()
+
+	for seed := int64(0); seed < 200; seed++ {
+		// Arrange: draw the rectangles and the options from the seed.
+		r := rand.New(rand.NewSource(seed))
+		rectangles := make([]binpack.Rectangle, 1+r.Intn(30))
+		for i := range rectangles {
+			rectangles[i] = binpack.Rectangle{Width: r.Intn(40), Height: r.Intn(40)}
+		}
+		opts := binpack.Options{
+			Score:          binpack.Score(r.Intn(4)),
+			TieBreak:       binpack.TieBreak(r.Intn(3)),
+			Grid:           r.Intn(8),
+			GridRounding:   binpack.GridRounding(r.Intn(3)),
+			EdgeMargin:     r.Intn(3),
+			MaxEvalPerRect: r.Intn(3) * r.Intn(20),
+			Gravity:        binpack.Gravity(r.Intn(5)),
+		}
+		if r.Intn(2) == 0 {
+			opts.MaxWidth, opts.MaxHeight = 40+r.Intn(200), 40+r.Intn(200)
+		}
+
+		// Act: pack as many as fit.
+		layout, unplaced := binpack.PackBestEffort(newTestPackable(rectangles), opts)
+
+		// Assert: every rectangle is placed or unplaced, and none overlap.
+		require.Len(t, layout.Placements, len(rectangles)-len(unplaced), "seed %d", seed)
+		requireNoOverlap(t, layout)
+	}
+}
+
+// TestPackWithOptions_Weights verifies that AreaWeight alone matches the
+// default score, and that DistanceWeight pulls the rectangles together
+// around the center.
+func TestPackWithOptions_Weights(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: twenty-five random rectangles and a measure of how far they spread from the center.
+	rectangles := randomRectangles(25)
+	spread := func(layout binpack.Layout) float64 {
+		var total float64
+		for _, p := range layout.Placements {
+			dx := float64(p.X) + float64(p.Width)/2 - float64(layout.Width)/2
+			dy := float64(p.Y) + float64(p.Height)/2 - float64(layout.Height)/2
+			total += dx*dx + dy*dy
+		}
+		return total
+	}
+
+	// Act: pack them with the default score and with each weight.
+	defaults, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+	require.NoError(t, err)
+	area, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{AreaWeight: 1})
+	require.NoError(t, err)
+	centered, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{AreaWeight: 1, DistanceWeight: 100})
+	require.NoError(t, err)
+
+	// Assert: the area weight alone changes nothing and the distance weight draws them in.
+	require.Equal(t, defaults, area)
+	require.Less(t, spread(centered), spread(defaults))
+	requireNoOverlap(t, centered)
+}
+
+// TestPackWithOptions_SingleRectangleTooLarge verifies that a lone rectangle
+// is checked against the limits, including grid rounding and the edge margin.
+func TestPackWithOptions_SingleRectangleTooLarge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rectangle binpack.Rectangle
+		opts      binpack.Options
+		err       error
+	}{
+		{name: "TooWide", rectangle: binpack.Rectangle{Width: 500, Height: 10}, opts: binpack.Options{MaxWidth: 256}, err: binpack.ErrTooLarge},
+		{name: "TooTall", rectangle: binpack.Rectangle{Width: 10, Height: 500}, opts: binpack.Options{MaxHeight: 256}, err: binpack.ErrTooLarge},
+		{name: "Fits", rectangle: binpack.Rectangle{Width: 250, Height: 10}, opts: binpack.Options{MaxWidth: 256, Grid: 64}},
+		{name: "GridRounding", rectangle: binpack.Rectangle{Width: 257, Height: 10}, opts: binpack.Options{MaxWidth: 300, Grid: 64}, err: binpack.ErrTooLarge},
+		{name: "EdgeMargin", rectangle: binpack.Rectangle{Width: 250, Height: 10}, opts: binpack.Options{MaxWidth: 256, EdgeMargin: 4}, err: binpack.ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: a Packable holding only the rectangle.
+			tp := newTestPackable([]binpack.Rectangle{tt.rectangle})
+
+			// Act: pack it within the limits.
+			_, err := binpack.PackWithOptions(tp, tt.opts)
+
+			// Assert: the rectangle is rejected only if it exceeds the rounded limits.
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestPackWithOptions_AutoOrient verifies that a landscape layout is rotated
+// to match a portrait frame, unless the limits forbid it.
+func TestPackWithOptions_AutoOrient(t *testing.T) {
+	t.Parallel()
+
+	rectangles := []binpack.Rectangle{{Width: 40, Height: 10}, {Width: 40, Height: 10}}
+	tests := []struct {
+		name          string
+		opts          binpack.Options
+		width, height int
+	}{
+		{name: "Portrait", opts: binpack.Options{AutoOrient: binpack.Rectangle{Width: 90, Height: 160}}, width: 20, height: 40},
+		{name: "Landscape", opts: binpack.Options{AutoOrient: binpack.Rectangle{Width: 160, Height: 90}}, width: 40, height: 20},
+		{name: "Limited", opts: binpack.Options{AutoOrient: binpack.Rectangle{Width: 90, Height: 160}, MaxHeight: 30}, width: 40, height: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack with the target orientation.
+			layout, err := binpack.PackWithOptions(newTestPackable(rectangles), tt.opts)
+
+			// Assert: the layout takes the orientation of the target unless the limits forbid it.
+			require.NoError(t, err)
+			require.Equal(t, tt.width, layout.Width)
+			require.Equal(t, tt.height, layout.Height)
+			requireNoOverlap(t, layout)
+		})
+	}
+}
+
+// TestPackInFreeRects verifies that rectangles are only placed within the
+// free areas, and that those that do not fit are reported.
+func TestPackInFreeRects(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: leave two free slots either side of a reserved column.
+	tp := newTestPackable([]binpack.Rectangle{
+		{Width: 40, Height: 40},
+		{Width: 30, Height: 30},
+		{Width: 50, Height: 50},
+	})
+	free := []image.Rectangle{image.Rect(0, 0, 40, 40), image.Rect(60, 0, 100, 40)}
+
+	// Act: pack into the free areas.
+	layout, unplaced := binpack.PackInFreeRects(tp, free)
+
+	// Assert: the largest rectangle fits nowhere, and the others fill one slot each.
+	require.Equal(t, []int{2}, unplaced)
+	require.Len(t, layout.Placements, 2)
+	for _, p := range layout.Placements {
+		r := image.Rect(p.X, p.Y, p.X+p.Width, p.Y+p.Height)
+		require.True(t, r.In(free[0]) || r.In(free[1]), "placement %v is not in a free area", r)
+	}
+	requireNoOverlap(t, layout)
+
+	// Act & Assert: with no free areas nothing is placed.
+	_, unplaced = binpack.PackInFreeRects(newTestPackable([]binpack.Rectangle{{Width: 1, Height: 1}}), nil)
+	require.Equal(t, []int{0}, unplaced)
+}
+
+// TestPackWithOptions_HeuristicAuto verifies that the algorithm is chosen by
+// the number of rectangles, and that other options keep the default
+// heuristic.
+func TestPackWithOptions_HeuristicAuto(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		rectangles []binpack.Rectangle
+		opts       binpack.Options
+		expected   func(rectangles []binpack.Rectangle) binpack.Layout
+	}{
+		{
+			name:       "Exhaustive",
+			rectangles: randomRectangles(6),
+			opts:       binpack.Options{Heuristic: binpack.HeuristicAuto},
+			expected: func(rectangles []binpack.Rectangle) binpack.Layout {
+				layout, _ := binpack.PackOptimal(newTestPackable(rectangles), 0, 0)
+				return layout
+			},
+		},
+		{
+			name:       "Deadline",
+			rectangles: randomRectangles(6),
+			opts:       binpack.Options{Heuristic: binpack.HeuristicAuto, Deadline: time.Now().Add(time.Hour)},
+			expected: func(rectangles []binpack.Rectangle) binpack.Layout {
+				layout, _ := binpack.PackOptimal(newTestPackable(rectangles), 0, 0)
+				return layout
+			},
+		},
+		{
+			name:       "Default",
+			rectangles: randomRectangles(20),
+			opts:       binpack.Options{Heuristic: binpack.HeuristicAuto},
+			expected: func(rectangles []binpack.Rectangle) binpack.Layout {
+				layout, _ := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{})
+				return layout
+			},
+		},
+		{
+			name:       "OtherOptions",
+			rectangles: randomRectangles(6),
+			opts:       binpack.Options{Heuristic: binpack.HeuristicAuto, EdgeMargin: 2},
+			expected: func(rectangles []binpack.Rectangle) binpack.Layout {
+				layout, _ := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{EdgeMargin: 2})
+				return layout
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack with the automatic heuristic.
+			layout, err := binpack.PackWithOptions(newTestPackable(tt.rectangles), tt.opts)
+
+			// Assert: the layout matches the one expected for the input.
+			require.NoError(t, err)
+			require.Equal(t, tt.expected(tt.rectangles), layout)
+		})
+	}
+}
+
+// TestPackBestEffort_HeuristicAutoShelves verifies that many rectangles are
+// placed on shelves within the limits.
+func TestPackBestEffort_HeuristicAutoShelves(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: six hundred random rectangles.
+	rectangles := randomRectangles(600)
+
+	// Act: pack as many as fit with the automatic heuristic.
+	layout, unplaced := binpack.PackBestEffort(newTestPackable(rectangles), binpack.Options{Heuristic: binpack.HeuristicAuto, MaxWidth: 3000})
+
+	// Assert: every rectangle is placed within the limit and the shelves are dense.
+	require.Empty(t, unplaced)
+	require.Len(t, layout.Placements, len(rectangles))
+	require.LessOrEqual(t, layout.Width, 3000)
+	require.Greater(t, float64(binpack.TotalArea(rectangles))/float64(layout.Width*layout.Height), 0.7)
+	requireNoOverlap(t, layout)
+}
+
+// BenchmarkPack_HeuristicAuto compares the default heuristic with
+// HeuristicAuto on either side of its thresholds.
+func BenchmarkPack_HeuristicAuto(b *testing.B) {
+	for _, n := range []int{6, 100, 500} {
+		rectangles := randomRectangles(n)
+		for name, heuristic := range map[string]binpack.Heuristic{"Default": binpack.HeuristicDefault, "Auto": binpack.HeuristicAuto} {
+			b.Run(strconv.Itoa(n)+"/"+name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					_, _ = binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{Heuristic: heuristic})
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package binpack
+
+import (
+	"image"
+	"slices"
+	"sort"
+)
+
+// Packer places rectangles one at a time into a fixed canvas, such as a live
+// texture atlas whose sprites come and go. It keeps a list of the free
+// rectangles of the canvas, in the style of the MaxRects algorithm, and puts
+// each new rectangle in the top-left corner of the free rectangle that fits
+// it most tightly, so placed rectangles never move when others are added or
+// removed, unless MaxFragmentation is set. Removing a rectangle returns its
+// area to the list as it is, so the free space fragments as rectangles
+// churn; Defragment repacks everything to recover it, moving the placed
+// rectangles, and Add and Remove call it themselves once the fragmentation
+// exceeds MaxFragmentation. Use NewPacker to create one.
+type Packer struct {
+	// MaxFragmentation is the ratio of free rectangles to placed rectangles
+	// above which Add and Remove call Defragment. Zero never defragments
+	// automatically.
+	MaxFragmentation float64
+	// MergeFree merges free rectangles that share a full edge, or overlap
+	// along one, into a single larger one after every Add and Remove. This
+	// keeps the areas returned by Remove from staying split into pieces too
+	// small for the rectangles that follow.
+	MergeFree bool
+
+	width, height int
+	placements    map[int]Placement
+	free          []image.Rectangle
+	next          int
+}
+
+// NewPacker returns a Packer for an empty width x height canvas.
+func NewPacker(width, height int) *Packer {
+	return &Packer{
+		width:      width,
+		height:     height,
+		placements: make(map[int]Placement),
+		free:       []image.Rectangle{image.Rect(0, 0, max(width, 0), max(height, 0))},
+	}
+}
+
+// Add places a rectangle into the free space of the canvas and returns the id
+// it is known by, which is never reused, and its position. Returns false if
+// there is no free rectangle large enough to hold it. Rectangles with no area
+// occupy no space and are placed at (0, 0).
+func (p *Packer) Add(r Rectangle) (id, x, y int, ok bool) {
+	if r.area64() > 0 {
+		var best, found = p.bestFit(r)
+		if !found {
+			return 0, 0, 0, false
+		}
+		x, y = best.X, best.Y
+		p.free = p.merge(splitFree(p.free, image.Rect(x, y, x+r.Width, y+r.Height)))
+	}
+
+	id = p.next
+	p.next++
+	p.placements[id] = Placement{Index: id, X: x, Y: y, Width: r.Width, Height: r.Height}
+	if p.compact() {
+		var placed = p.placements[id]
+		x, y = placed.X, placed.Y
+	}
+	return id, x, y, true
+}
+
+// Remove frees the area of the rectangle with the given id. Returns false if
+// there is no such rectangle.
+func (p *Packer) Remove(id int) bool {
+	var placed, ok = p.placements[id]
+	if !ok {
+		return false
+	}
+	delete(p.placements, id)
+	if placed.Width > 0 && placed.Height > 0 {
+		p.free = p.merge(pruneFree(append(p.free, image.Rect(placed.X, placed.Y, placed.X+placed.Width, placed.Y+placed.Height))))
+	}
+	p.compact()
+	return true
+}
+
+// Fragmentation returns the ratio of free rectangles to placed rectangles,
+// which grows as rectangles are removed and leave holes behind. Returns 0 if
+// the canvas is empty.
+func (p *Packer) Fragmentation() float64 {
+	if len(p.placements) == 0 {
+		return 0
+	}
+	return float64(len(p.free)) / float64(len(p.placements))
+}
+
+// Defragment repacks every rectangle from scratch, which can move all of them,
+// and rebuilds the free space. Returns false, leaving the rectangles where
+// they are, if they no longer fit in the canvas when repacked.
+func (p *Packer) Defragment() bool {
+	var ids = make([]int, 0, len(p.placements))
+	for id := range p.placements {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var sizes = make(rectangleSlice, len(ids))
+	for i, id := range ids {
+		sizes[i] = Rectangle{Width: p.placements[id].Width, Height: p.placements[id].Height}
+	}
+
+	var layout, unplaced, _ = pack(sizes, Options{MaxWidth: p.width, MaxHeight: p.height})
+	if len(unplaced) > 0 {
+		return false
+	}
+	p.free = []image.Rectangle{image.Rect(0, 0, p.width, p.height)}
+	for _, placed := range layout.Placements {
+		placed.Index = ids[placed.Index]
+		p.placements[placed.Index] = placed
+		if placed.Width > 0 && placed.Height > 0 {
+			p.free = splitFree(p.free, image.Rect(placed.X, placed.Y, placed.X+placed.Width, placed.Y+placed.Height))
+		}
+	}
+	return true
+}
+
+// Layout returns the placements in the canvas, with each Index holding the id
+// returned by Add, in ascending order of id.
+func (p *Packer) Layout() Layout {
+	var layout = Layout{Width: p.width, Height: p.height, Placements: make([]Placement, 0, len(p.placements))}
+	for _, placed := range p.placements {
+		layout.Placements = append(layout.Placements, placed)
+	}
+	sort.Slice(layout.Placements, func(i, j int) bool {
+		return layout.Placements[i].Index < layout.Placements[j].Index
+	})
+	return layout
+}
+
+// compact calls Defragment if the free space is more fragmented than
+// MaxFragmentation allows, and returns true if the rectangles were repacked.
+func (p *Packer) compact() bool {
+	return p.MaxFragmentation > 0 && p.Fragmentation() > p.MaxFragmentation && p.Defragment()
+}
+
+// merge returns free with the rectangles merged if MergeFree is set.
+func (p *Packer) merge(free []image.Rectangle) []image.Rectangle {
+	if !p.MergeFree {
+		return free
+	}
+	return mergeFree(free)
+}
+
+// bestFit returns the top-left corner of the free rectangle that holds r with
+// the least space left over along its shorter side, breaking ties by the
+// longer side and then by position, or false if none is large enough.
+func (p *Packer) bestFit(r Rectangle) (image.Point, bool) {
+	var best image.Point
+	var bestShort, bestLong int
+	var found bool
+	for _, f := range p.free {
+		if f.Dx() < r.Width || f.Dy() < r.Height {
+			continue
+		}
+		var short, long = f.Dx() - r.Width, f.Dy() - r.Height
+		if short > long {
+			short, long = long, short
+		}
+		var better = !found || short < bestShort || (short == bestShort && long < bestLong) ||
+			(short == bestShort && long == bestLong && (f.Min.Y < best.Y || (f.Min.Y == best.Y && f.Min.X < best.X)))
+		if better {
+			best, bestShort, bestLong, found = f.Min, short, long, true
+		}
+	}
+	return best, found
+}
+
+// splitFree returns the free rectangles left once used is occupied. Each free
+// rectangle that overlaps used is replaced by the up to four maximal
+// rectangles beside it, and rectangles held within others are dropped.
+func splitFree(free []image.Rectangle, used image.Rectangle) []image.Rectangle {
+	var split = make([]image.Rectangle, 0, len(free)+4)
+	for _, f := range free {
+		if !f.Overlaps(used) {
+			split = append(split, f)
+			continue
+		}
+		if used.Min.X > f.Min.X {
+			split = append(split, image.Rect(f.Min.X, f.Min.Y, used.Min.X, f.Max.Y))
+		}
+		if used.Max.X < f.Max.X {
+			split = append(split, image.Rect(used.Max.X, f.Min.Y, f.Max.X, f.Max.Y))
+		}
+		if used.Min.Y > f.Min.Y {
+			split = append(split, image.Rect(f.Min.X, f.Min.Y, f.Max.X, used.Min.Y))
+		}
+		if used.Max.Y < f.Max.Y {
+			split = append(split, image.Rect(f.Min.X, used.Max.Y, f.Max.X, f.Max.Y))
+		}
+	}
+	return pruneFree(split)
+}
+
+// pruneFree drops the free rectangles that are held within another, keeping
+// the first of any duplicates.
+func pruneFree(free []image.Rectangle) []image.Rectangle {
+	var pruned = make([]image.Rectangle, 0, len(free))
+	for i, f := range free {
+		var held = slices.ContainsFunc(free, func(g image.Rectangle) bool { return g != f && f.In(g) }) ||
+			slices.Contains(free[:i], f)
+		if !held {
+			pruned = append(pruned, f)
+		}
+	}
+	return pruned
+}
+
+// mergeFree repeatedly replaces pairs of free rectangles that span the same
+// rows and touch or overlap horizontally, or span the same columns and touch
+// or overlap vertically, with their union, until no such pair remains.
+func mergeFree(free []image.Rectangle) []image.Rectangle {
+	free = slices.Clone(free)
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(free) && !merged; i++ {
+			for j := i + 1; j < len(free); j++ {
+				var a, b = free[i], free[j]
+				var rows = a.Min.Y == b.Min.Y && a.Max.Y == b.Max.Y && a.Min.X <= b.Max.X && b.Min.X <= a.Max.X
+				var columns = a.Min.X == b.Min.X && a.Max.X == b.Max.X && a.Min.Y <= b.Max.Y && b.Min.Y <= a.Max.Y
+				if rows || columns {
+					free[i] = a.Union(b)
+					free = slices.Delete(free, j, j+1)
+					merged = true
+					break
+				}
+			}
+		}
+	}
+	return pruneFree(free)
+}
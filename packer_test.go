@@ -0,0 +1,122 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPacker verifies that rectangles are added into free space without
+// moving the others, and that a full canvas rejects further rectangles.
+func TestPacker(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: an empty 20x20 canvas.
+	packer := binpack.NewPacker(20, 20)
+
+	// Act: fill the canvas with four tiles, then try a fifth.
+	var ids []int
+	for range 4 {
+		id, _, _, ok := packer.Add(binpack.Rectangle{Width: 10, Height: 10})
+		require.True(t, ok)
+		ids = append(ids, id)
+	}
+	_, _, _, full := packer.Add(binpack.Rectangle{Width: 10, Height: 10})
+	require.True(t, packer.Remove(ids[1]))
+	id, x, y, ok := packer.Add(binpack.Rectangle{Width: 10, Height: 10})
+
+	// Assert: the new tile should take the place of the removed one.
+	require.False(t, full)
+	require.True(t, ok)
+	require.Equal(t, 4, id)
+	require.Equal(t, binpack.Placement{Index: 4, X: x, Y: y, Width: 10, Height: 10}, packer.Layout().Placements[3])
+	require.False(t, packer.Remove(ids[1]))
+	requireNoOverlap(t, packer.Layout())
+}
+
+// TestPacker_Defragment verifies that repacking recovers space fragmented by
+// removals, and that MaxFragmentation triggers it automatically.
+func TestPacker_Defragment(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		maxFragmentation float64
+	}{
+		{name: "Manual"},
+		{name: "Automatic", maxFragmentation: 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: fill a strip with small tiles and remove every other one,
+			// leaving no gap wide enough for a wide tile.
+			packer := binpack.NewPacker(60, 10)
+			packer.MaxFragmentation = tt.maxFragmentation
+			var ids []int
+			for range 6 {
+				id, _, _, ok := packer.Add(binpack.Rectangle{Width: 10, Height: 10})
+				require.True(t, ok)
+				ids = append(ids, id)
+			}
+			for i := 0; i < len(ids); i += 2 {
+				require.True(t, packer.Remove(ids[i]))
+			}
+
+			// Act: defragment by hand unless it is automatic, then add a wide tile.
+			if tt.maxFragmentation == 0 {
+				require.Greater(t, packer.Fragmentation(), 0.5)
+				require.True(t, packer.Defragment())
+			}
+			_, _, _, ok := packer.Add(binpack.Rectangle{Width: 30, Height: 10})
+
+			// Assert: the wide tile fits and the fragmentation is recovered.
+			require.True(t, ok)
+			require.LessOrEqual(t, packer.Fragmentation(), 0.5)
+			require.Len(t, packer.Layout().Placements, 4)
+			requireNoOverlap(t, packer.Layout())
+		})
+	}
+}
+
+// TestPacker_MergeFree verifies that adjacent free areas are merged so that a
+// rectangle spanning them fits.
+func TestPacker_MergeFree(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		mergeFree bool
+		want      bool
+	}{
+		{name: "Split", mergeFree: false, want: false},
+		{name: "Merged", mergeFree: true, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: fill a strip with tiles and remove two neighbours.
+			packer := binpack.NewPacker(60, 10)
+			packer.MergeFree = tt.mergeFree
+			for range 6 {
+				_, _, _, ok := packer.Add(binpack.Rectangle{Width: 10, Height: 10})
+				require.True(t, ok)
+			}
+			require.True(t, packer.Remove(1))
+			require.True(t, packer.Remove(2))
+
+			// Act: add a tile as wide as the two removed.
+			_, x, _, ok := packer.Add(binpack.Rectangle{Width: 20, Height: 10})
+
+			// Assert: the tile only fits in the gap if the free areas are merged.
+			require.Equal(t, tt.want, ok)
+			if ok {
+				require.Equal(t, 10, x)
+				require.Zero(t, packer.Fragmentation())
+			}
+		})
+	}
+}
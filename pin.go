@@ -0,0 +1,66 @@
+package binpack
+
+// Pinner is an optional interface for Packables with rectangles fixed at a
+// given position, such as anchored elements in a layout tool. Pinned
+// rectangles are placed first, exactly where requested, and the other
+// rectangles flow around them. Pinned rectangles may overlap each other, and
+// do not count towards Options.Limit. HeuristicGrid ignores the pins.
+type Pinner interface {
+	// Pin returns the position of the rectangle at index n, or false if the
+	// rectangle is free to move.
+	Pin(n int) (x, y int, ok bool)
+}
+
+// PinReport describes the cost of the pinned rectangles in a layout. Set
+// Options.PinReport to collect it.
+type PinReport struct {
+	// Displaced lists the rectangles that grew the layout because a pinned
+	// rectangle blocked a better position, in the order they were placed.
+	Displaced []Displacement
+	// Growth is the total area by which the displaced rectangles grew the
+	// layout.
+	Growth int64
+}
+
+// Displacement describes a rectangle that was forced to grow the layout by a
+// pinned rectangle.
+type Displacement struct {
+	// Index is the index of the displaced rectangle.
+	Index int
+	// Growth is the area by which the layout grew beyond what the rectangle
+	// would have needed had the pinned rectangles not been in the way.
+	Growth int64
+}
+
+// collectPins returns the placements of the pinned rectangles with area
+// among positions, in the order of positions.
+func collectPins(p Packable, positions []int) []placement {
+	var pinner, ok = optional[Pinner](p)
+	if !ok {
+		return nil
+	}
+	var pins []placement
+	for _, position := range positions {
+		var r = p.Rectangle(position)
+		if x, y, ok := pinner.Pin(position); ok && r.area64() > 0 {
+			pins = append(pins, placement{position: position, x: x, y: y, width: r.Width, height: r.Height})
+		}
+	}
+	return pins
+}
+
+// displacement returns the area by which placing r at (x, y) grows the
+// bounding box b beyond its best position were only the unpinned placements
+// obstacles. Returns zero if the pins did not force any growth.
+func displacement(r Rectangle, x, y int, placements []placement, candidates *candidateSet, unpinned []placement, b bounds, c constraints, opts Options) int64 {
+	c.blocking = unpinned
+	var freeX, freeY, found = findBestPlacement(b, r, placements, candidates, c, opts)
+	if !found {
+		return 0
+	}
+	var area = func(x, y int) int64 {
+		var bb = expandBoundsForPlacement(placement{x: x, y: y, width: r.Width, height: r.Height}, b)
+		return int64(bb.maxX-bb.minX) * int64(bb.maxY-bb.minY)
+	}
+	return max(area(x, y)-area(freeX, freeY), 0)
+}
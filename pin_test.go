@@ -0,0 +1,118 @@
+package binpack_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testPinPackable extends testPackable with pinned rectangles.
+type testPinPackable struct {
+	*testPackable
+	pins map[int]image.Point
+}
+
+// Ensure that testPinPackable implements the binpack.Pinner interface.
+var _ binpack.Pinner = (*testPinPackable)(nil)
+
+// Pin returns the position of the rectangle at the specified index, if pinned.
+func (tp *testPinPackable) Pin(n int) (int, int, bool) {
+	pin, ok := tp.pins[n]
+	return pin.X, pin.Y, ok
+}
+
+// TestPinner verifies that pinned rectangles keep their relative positions and
+// that the others flow around them.
+func TestPinner(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pin two small rectangles apart from each other.
+	tp := &testPinPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 10, Height: 10},
+			{Width: 40, Height: 40},
+			{Width: 10, Height: 10},
+			{Width: 30, Height: 30},
+		}),
+		pins: map[int]image.Point{0: {X: -20, Y: 10}, 2: {X: 40, Y: 70}},
+	}
+
+	// Act: pack the rectangles.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{})
+
+	// Assert: the pins should keep their offset and nothing should overlap.
+	require.NoError(t, err)
+	require.Equal(t, 60, tp.placements[2].x-tp.placements[0].x)
+	require.Equal(t, 60, tp.placements[2].y-tp.placements[0].y)
+	require.Len(t, layout.Placements, 4)
+	requireNoOverlap(t, layout)
+}
+
+// TestPinner_KeepPinPositions verifies that pinned rectangles keep their
+// exact, possibly negative, coordinates and the others flow around them.
+func TestPinner_KeepPinPositions(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pin two rectangles, one at a negative position.
+	tp := &testPinPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 10, Height: 10},
+			{Width: 40, Height: 40},
+			{Width: 10, Height: 10},
+			{Width: 30, Height: 30},
+		}),
+		pins: map[int]image.Point{0: {X: -20, Y: 10}, 2: {X: 40, Y: 70}},
+	}
+
+	// Act: pack, keeping the pinned positions.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{KeepPinPositions: true})
+
+	// Assert: the layout should span the bounding box of the placements.
+	require.NoError(t, err)
+	require.Equal(t, struct{ x, y int }{-20, 10}, tp.placements[0])
+	require.Equal(t, struct{ x, y int }{40, 70}, tp.placements[2])
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	for i, p := range layout.Placements {
+		if i == 0 || p.X < minX {
+			minX = p.X
+		}
+		if i == 0 || p.Y < minY {
+			minY = p.Y
+		}
+		maxX, maxY = max(maxX, p.X+p.Width), max(maxY, p.Y+p.Height)
+	}
+	require.Equal(t, -20, minX)
+	require.Equal(t, maxX-minX, layout.Width)
+	require.Equal(t, maxY-minY, layout.Height)
+	requireNoOverlap(t, layout)
+}
+
+// TestPinner_Report verifies that a rectangle forced to grow the layout by a
+// pin is reported.
+func TestPinner_Report(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: pin a frame with a small obstacle in the middle of the space inside it.
+	tp := &testPinPackable{
+		testPackable: newTestPackable([]binpack.Rectangle{
+			{Width: 100, Height: 10},
+			{Width: 100, Height: 10},
+			{Width: 10, Height: 10},
+			{Width: 80, Height: 80},
+		}),
+		pins: map[int]image.Point{0: {X: 0, Y: 0}, 1: {X: 0, Y: 90}, 2: {X: 45, Y: 45}},
+	}
+	report := &binpack.PinReport{}
+
+	// Act: pack the rectangles while collecting the report.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{PinReport: report})
+
+	// Assert: the large rectangle should be displaced out of the frame.
+	require.NoError(t, err)
+	requireNoOverlap(t, layout)
+	require.Equal(t, []binpack.Displacement{{Index: 3, Growth: int64(layout.Width*layout.Height - 100*100)}}, report.Displaced)
+	require.Equal(t, report.Displaced[0].Growth, report.Growth)
+	require.Positive(t, report.Growth)
+}
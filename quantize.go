@@ -0,0 +1,134 @@
+package binpack
+
+import (
+	"image"
+	"sort"
+)
+
+// quantize rounds the position of every placement in l to the nearest
+// multiple of opts.Quantize, measured from the edge margin, or from the
+// origin of the region holding the placement with Regions. A placement that
+// would then overlap one already rounded is nudged right or down to later
+// multiples until it is clear, so that no overlap is introduced; placements
+// that overlapped before, such as over a background, are left to overlap.
+// Pinned rectangles of p keep their positions, and so does a placement whose
+// rounded position would leave the regions or be vetoed by an Allower, the
+// others then being rounded around it. Positions are converted to the
+// coordinates used while packing by adding originX and originY. The
+// dimensions grow to contain the placements. A Quantize of one or less
+// leaves l unchanged.
+func (l Layout) quantize(p Packable, opts Options, originX, originY int) Layout {
+	var q = opts.Quantize
+	if q <= 1 {
+		return l
+	}
+
+	var fixed = make([]bool, len(l.Placements))
+	if pinner, ok := optional[Pinner](p); ok {
+		for i, placed := range l.Placements {
+			_, _, fixed[i] = pinner.Pin(placed.Index)
+		}
+	}
+	var allower, _ = optional[Allower](p)
+	var valid = func(c placement) bool {
+		return opts.contains(c) && (allower == nil || allower.Allowed(c.position, c.x+originX, c.y+originY))
+	}
+
+	// Each placement that cannot be rounded is fixed in place, and the others are rounded again around it.
+	var rounded []placement
+	for {
+		var failed int
+		rounded, failed = l.round(q, opts, fixed, valid)
+		if failed < 0 {
+			break
+		}
+		fixed[failed] = true
+	}
+
+	var quantized = l
+	quantized.Placements = make([]Placement, len(l.Placements))
+	for i, r := range rounded {
+		quantized.Placements[i] = Placement{Index: r.position, X: r.x, Y: r.y, Width: r.width, Height: r.height}
+		if r.width > 0 && r.height > 0 {
+			quantized.Width = max(quantized.Width, r.x+r.width+opts.margin())
+			quantized.Height = max(quantized.Height, r.y+r.height+opts.margin())
+		}
+	}
+	return quantized
+}
+
+// round rounds the placements of l that are not fixed to multiples of q, as
+// described by quantize, and returns the rounded placements in the same
+// order. The fixed placements are settled first, so the others are nudged
+// around them. Returns the index of the first placement whose rounded
+// position is not valid, or -1 if every position is.
+func (l Layout) round(q int, opts Options, fixed []bool, valid func(c placement) bool) ([]placement, int) {
+	// Visit the fixed placements first, then the others from left to right so nudges move away from settled placements.
+	var order = make([]int, len(l.Placements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		var a, b = l.Placements[order[i]], l.Placements[order[j]]
+		if fixed[order[i]] != fixed[order[j]] {
+			return fixed[order[i]]
+		}
+		return a.X < b.X || (a.X == b.X && a.Y < b.Y)
+	})
+
+	var original = make([]placement, len(l.Placements))
+	var rounded = make([]placement, len(l.Placements))
+	var settled = make([]int, 0, len(l.Placements))
+	for _, i := range order {
+		var p = l.Placements[i]
+		original[i] = newPlacement(p)
+		var candidate = original[i]
+		if p.Width <= 0 || p.Height <= 0 || fixed[i] {
+			rounded[i] = candidate
+			settled = append(settled, i)
+			continue
+		}
+		var origin = opts.quantumOrigin(original[i])
+		candidate.x = origin.X + roundToGrid(p.X-origin.X, q, RoundNearest)
+		candidate.y = origin.Y + roundToGrid(p.Y-origin.Y, q, RoundNearest)
+
+		// Nudge the candidate right or down, whichever clears the overlap sooner, to
+		// the next multiple. Each move passes an edge of a settled placement, so the
+		// loop terminates.
+		for nudged := true; nudged; {
+			nudged = false
+			for _, j := range settled {
+				var other = rounded[j]
+				if doRectanglesIntersect(original[i], original[j]) || !doRectanglesIntersect(candidate, other) {
+					continue
+				}
+				var overlapX = other.x + other.width - candidate.x
+				var overlapY = other.y + other.height - candidate.y
+				if overlapX <= overlapY {
+					candidate.x = origin.X + roundToGrid(candidate.x+overlapX-origin.X, q, RoundUp)
+				} else {
+					candidate.y = origin.Y + roundToGrid(candidate.y+overlapY-origin.Y, q, RoundUp)
+				}
+				nudged = true
+			}
+		}
+		if !valid(candidate) {
+			return nil, i
+		}
+		rounded[i] = candidate
+		settled = append(settled, i)
+	}
+	return rounded, -1
+}
+
+// quantumOrigin returns the point from which the multiples of Quantize are
+// measured for the placement p: the origin of the first region holding its
+// top-left corner, or the corner inside the edge margin.
+func (o Options) quantumOrigin(p placement) image.Point {
+	for _, region := range o.Regions {
+		if image.Pt(p.x, p.y).In(region) {
+			return region.Min
+		}
+	}
+	return image.Pt(o.margin(), o.margin())
+}
@@ -0,0 +1,113 @@
+package binpack
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format identifies the encoding of the rectangles read by PackReader.
+type Format int
+
+const (
+	// FormatCSV reads one rectangle per record as "w,h". A header record of
+	// "w,h" is skipped.
+	FormatCSV Format = iota
+	// FormatNDJSON reads one rectangle per line as {"w":..,"h":..}.
+	FormatNDJSON
+)
+
+// ErrInvalidInput is returned when PackReader cannot decode a rectangle.
+var ErrInvalidInput = errors.New("binpack: invalid input")
+
+// PackReader decodes rectangles from r in the given format and packs them as
+// Pack does. Rectangles are decoded as they are read, so the input is never
+// held in memory as a whole. The placements refer to the rectangles in the
+// order they were read.
+func PackReader(r io.Reader, format Format) (Layout, error) {
+	var rects rectangleSlice
+	var err error
+	switch format {
+	case FormatCSV:
+		rects, err = readCSV(r)
+	case FormatNDJSON:
+		rects, err = readNDJSON(r)
+	default:
+		return Layout{}, fmt.Errorf("%w: unknown format %d", ErrInvalidInput, format)
+	}
+	if err != nil {
+		return Layout{}, err
+	}
+
+	var layout, _, _ = pack(rects, Options{})
+	return layout, nil
+}
+
+// readCSV decodes rectangles from "w,h" records.
+func readCSV(r io.Reader) (rectangleSlice, error) {
+	var reader = csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	var rects rectangleSlice
+	for first := true; ; first = false {
+		var record, err = reader.Read()
+		if err == io.EOF {
+			return rects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		if first && strings.EqualFold(record[0], "w") && strings.EqualFold(record[1], "h") {
+			continue
+		}
+
+		var line, _ = reader.FieldPos(0)
+		var width, widthErr = strconv.Atoi(strings.TrimSpace(record[0]))
+		var height, heightErr = strconv.Atoi(strings.TrimSpace(record[1]))
+		if widthErr != nil || heightErr != nil {
+			return nil, fmt.Errorf("%w: line %d: %q is not a size", ErrInvalidInput, line, strings.Join(record, ","))
+		}
+		if err := appendRectangle(&rects, width, height, line); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readNDJSON decodes rectangles from {"w":..,"h":..} values.
+func readNDJSON(r io.Reader) (rectangleSlice, error) {
+	var decoder = json.NewDecoder(r)
+
+	var rects rectangleSlice
+	for line := 1; ; line++ {
+		var value struct {
+			W, H *int
+		}
+		if err := decoder.Decode(&value); err == io.EOF {
+			return rects, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidInput, line, err)
+		}
+		if value.W == nil || value.H == nil {
+			return nil, fmt.Errorf("%w: line %d: missing w or h", ErrInvalidInput, line)
+		}
+		if err := appendRectangle(&rects, *value.W, *value.H, line); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// appendRectangle appends a width x height rectangle to rects, rejecting
+// negative dimensions.
+func appendRectangle(rects *rectangleSlice, width, height, line int) error {
+	if width < 0 || height < 0 {
+		return fmt.Errorf("%w: line %d: negative size %dx%d", ErrInvalidInput, line, width, height)
+	}
+	*rects = append(*rects, Rectangle{Width: width, Height: height})
+	return nil
+}
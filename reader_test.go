@@ -0,0 +1,68 @@
+package binpack_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackReader verifies that rectangles are decoded from each format and
+// packed.
+func TestPackReader(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format binpack.Format
+		input  string
+	}{
+		{name: "CSV", format: binpack.FormatCSV, input: "w,h\n100,50\n50, 50\n"},
+		{name: "NDJSON", format: binpack.FormatNDJSON, input: "{\"w\":100,\"h\":50}\n{\"w\":50,\"h\":50,\"name\":\"icon\"}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack the rectangles read from the input.
+			layout, err := binpack.PackReader(strings.NewReader(tt.input), tt.format)
+
+			// Assert: both rectangles should be packed side by side.
+			require.NoError(t, err)
+			require.Equal(t, 150, layout.Width)
+			require.Equal(t, 50, layout.Height)
+			require.Len(t, layout.Placements, 2)
+			require.Equal(t, 100, layout.Placements[0].Width)
+		})
+	}
+}
+
+// TestPackReader_Invalid verifies that malformed input is rejected.
+func TestPackReader_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		format binpack.Format
+		input  string
+	}{
+		{name: "CSV not a number", format: binpack.FormatCSV, input: "10,10\nten,10\n"},
+		{name: "CSV wrong field count", format: binpack.FormatCSV, input: "10,10,10\n"},
+		{name: "CSV negative", format: binpack.FormatCSV, input: "-1,10\n"},
+		{name: "NDJSON malformed", format: binpack.FormatNDJSON, input: "{\"w\":10\n"},
+		{name: "NDJSON missing height", format: binpack.FormatNDJSON, input: "{\"w\":10}\n"},
+		{name: "Unknown format", format: binpack.Format(-1), input: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: pack the rectangles read from the input.
+			_, err := binpack.PackReader(strings.NewReader(tt.input), tt.format)
+
+			// Assert: the input should be rejected.
+			require.ErrorIs(t, err, binpack.ErrInvalidInput)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package binpack
+
+import "image"
+
+// Reflow repacks the rectangles in p under new options, such as a narrower
+// MaxWidth after a window is resized, while keeping each rectangle as close
+// as it can to its position in prev, matched by index. The previous positions
+// are used as Options.Hints, taking precedence over any hints in opts, so
+// they decide between equally scoring candidates without trading away
+// compactness. Rectangles that do not fit within the new limits are left out
+// of the layout, as with PackBestEffort.
+func Reflow(prev Layout, p Packable, opts Options) Layout {
+	var hints = make([]image.Point, p.Len())
+	for i := range hints {
+		hints[i] = image.Pt(Unplaced, Unplaced)
+		if i < len(opts.Hints) {
+			hints[i] = opts.Hints[i]
+		}
+	}
+	for _, placement := range prev.Placements {
+		if placement.Index >= 0 && placement.Index < len(hints) {
+			hints[placement.Index] = image.Pt(placement.X, placement.Y)
+		}
+	}
+	opts.Hints = hints
+
+	var layout, _ = PackBestEffort(p, opts)
+	return layout
+}
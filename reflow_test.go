@@ -0,0 +1,29 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReflow verifies that rectangles keep their previous positions when they
+// still can, and that every rectangle is placed within new limits.
+func TestReflow(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: twenty random rectangles and their layout within 700 pixels.
+	rectangles := randomRectangles(20)
+	prev, err := binpack.PackWithOptions(newTestPackable(rectangles), binpack.Options{MaxWidth: 700})
+	require.NoError(t, err)
+
+	// Act: reflow the layout within the same and a narrower limit.
+	same := binpack.Reflow(prev, newTestPackable(rectangles), binpack.Options{MaxWidth: 700})
+	narrower := binpack.Reflow(prev, newTestPackable(rectangles), binpack.Options{MaxWidth: 600})
+
+	// Assert: nothing moves within the same limit, and every rectangle fits the narrower one.
+	require.Empty(t, binpack.Diff(prev, same))
+	require.Len(t, narrower.Placements, len(rectangles))
+	require.LessOrEqual(t, narrower.Width, 600)
+	requireNoOverlap(t, narrower)
+}
@@ -0,0 +1,62 @@
+package binpack
+
+import (
+	"image"
+	"slices"
+)
+
+// PackInFreeRects places the rectangles in p only within the free areas of an
+// existing canvas, such as the slots of a persistent atlas that are not
+// reserved, and returns the layout and the indices of the rectangles that did
+// not fit, in ascending order. Rectangles may span free areas that touch.
+// Positions are canvas coordinates, as with Options.Regions, which this is a
+// shorthand for.
+func PackInFreeRects(p Packable, free []image.Rectangle) (Layout, []int) {
+	if len(free) == 0 {
+		var unplaced = make([]int, 0, p.Len())
+		var skip = skipper(p)
+		for i := 0; i < p.Len(); i++ {
+			if !skip(i) {
+				unplaced = append(unplaced, i)
+			}
+		}
+		return Layout{}, unplaced
+	}
+	return PackBestEffort(p, Options{Regions: free})
+}
+
+// covers returns true if r lies entirely within the union of regions.
+func covers(regions []image.Rectangle, r image.Rectangle) bool {
+	// Clip the regions to r, and split r into cells along their edges.
+	var clipped = make([]image.Rectangle, 0, len(regions))
+	var xs, ys = []int{r.Min.X, r.Max.X}, []int{r.Min.Y, r.Max.Y}
+	for _, region := range regions {
+		if region = region.Intersect(r); region.Empty() {
+			continue
+		}
+		if region == r {
+			return true
+		}
+		clipped = append(clipped, region)
+		xs = append(xs, region.Min.X, region.Max.X)
+		ys = append(ys, region.Min.Y, region.Max.Y)
+	}
+	if len(clipped) == 0 {
+		return false
+	}
+	slices.Sort(xs)
+	slices.Sort(ys)
+	xs = slices.Compact(xs)
+	ys = slices.Compact(ys)
+
+	// Every cell must be inside one of the clipped regions.
+	for i := 0; i+1 < len(xs); i++ {
+		for j := 0; j+1 < len(ys); j++ {
+			var cell = image.Rect(xs[i], ys[j], xs[i+1], ys[j+1])
+			if !slices.ContainsFunc(clipped, cell.In) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,140 @@
+package binpack
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// svgFontSize is the largest font size RenderSVG labels rectangles with, and
+// svgCharWidth is the width of a character as a fraction of the font size,
+// which is roughly that of a monospace font.
+const (
+	svgFontSize  = 12
+	svgCharWidth = 0.6
+)
+
+// RenderSVG draws the outline of every placement in the layout as an SVG
+// image, for debugging. Each rectangle is labelled with names[i] for the
+// rectangle at index i, or with its index if names is too short or the name
+// is empty. Labels too long for their rectangle are truncated with an
+// ellipsis.
+func RenderSVG(layout Layout, names []string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", layout.Width, layout.Height, layout.Width, layout.Height)
+	for _, p := range layout.Placements {
+		if p.Width <= 0 || p.Height <= 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"none\" stroke=\"black\"/>\n", p.X, p.Y, p.Width, p.Height)
+
+		var size = min(svgFontSize, p.Height)
+		var label = truncateLabel(placementLabel(p.Index, names), int(float64(p.Width)/(svgCharWidth*float64(size))), "…")
+		if label == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "<text x=\"%d\" y=\"%d\" font-size=\"%d\" font-family=\"monospace\">", p.X, p.Y+size, size)
+		xml.EscapeText(&b, []byte(label))
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+// RenderASCII draws the layout as text, for debugging, with each character
+// standing for a cell x cell square. Every placement is outlined with '+',
+// '-' and '|', and labelled inside its top edge like RenderSVG, with long
+// labels truncated with "..." and characters outside ASCII drawn as '?'.
+// Placements too small to outline are filled with '#', and empty cells are
+// drawn as '.'. Returns an empty string if cell is not positive.
+func RenderASCII(layout Layout, cell int, names []string) string {
+	if cell <= 0 {
+		return ""
+	}
+	var rows = make([][]byte, (layout.Height+cell-1)/cell)
+	for row := range rows {
+		rows[row] = bytes.Repeat([]byte{'.'}, (layout.Width+cell-1)/cell)
+	}
+	var set = func(row, col int, c byte) {
+		if row >= 0 && row < len(rows) && col >= 0 && col < len(rows[row]) {
+			rows[row][col] = c
+		}
+	}
+
+	for _, p := range layout.Placements {
+		if p.Width <= 0 || p.Height <= 0 {
+			continue
+		}
+		var top, left = p.Y / cell, p.X / cell
+		var bottom, right = (p.Y + p.Height - 1) / cell, (p.X + p.Width - 1) / cell
+		for row := top; row <= bottom; row++ {
+			for col := left; col <= right; col++ {
+				switch {
+				case row == top || row == bottom:
+					if col == left || col == right {
+						set(row, col, '+')
+					} else {
+						set(row, col, '-')
+					}
+				case col == left || col == right:
+					set(row, col, '|')
+				default:
+					set(row, col, ' ')
+				}
+			}
+		}
+		if bottom-top < 2 || right-left < 2 {
+			for row := top; row <= bottom; row++ {
+				for col := left; col <= right; col++ {
+					set(row, col, '#')
+				}
+			}
+			continue
+		}
+		var label = truncateLabel(placementLabel(p.Index, names), right-left-1, "...")
+		for i, r := range []rune(label) {
+			var c = byte('?')
+			if r < utf8.RuneSelf {
+				c = byte(r)
+			}
+			set(top+1, left+1+i, c)
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		b.Write(row)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// placementLabel returns the name of the rectangle at index n, or its index
+// if it has no name.
+func placementLabel(n int, names []string) string {
+	if n >= 0 && n < len(names) && names[n] != "" {
+		return names[n]
+	}
+	return strconv.Itoa(n)
+}
+
+// truncateLabel shortens label to at most width characters, ending it with
+// ellipsis if it was cut. Labels that cannot fit the ellipsis are cut
+// without it.
+func truncateLabel(label string, width int, ellipsis string) string {
+	if width <= 0 {
+		return ""
+	}
+	var runes = []rune(label)
+	if len(runes) <= width {
+		return label
+	}
+	var marker = utf8.RuneCountInString(ellipsis)
+	if width <= marker {
+		return string(runes[:width])
+	}
+	return string(runes[:width-marker]) + ellipsis
+}
@@ -0,0 +1,60 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRenderASCII verifies that placements are outlined and labelled by name,
+// with long names truncated.
+func TestRenderASCII(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: three rectangles, the last too small to label.
+	layout := binpack.Layout{
+		Width:  16,
+		Height: 4,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 6, Height: 4},
+			{Index: 1, X: 6, Y: 0, Width: 8, Height: 3},
+			{Index: 2, X: 14, Y: 0, Width: 1, Height: 1},
+		},
+	}
+
+	// Act: render the layout with names for two of them.
+	text := binpack.RenderASCII(layout, 1, []string{"hero_idle", "coin"})
+
+	// Assert: the unnamed rectangle should be too small to label.
+	require.Equal(t, ""+
+		"+----++------+#.\n"+
+		"|h...||coin  |..\n"+
+		"|    |+------+..\n"+
+		"+----+..........\n", text)
+}
+
+// TestRenderSVG verifies that placements are drawn with escaped, truncated
+// labels, falling back to the index.
+func TestRenderSVG(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: two rectangles, one named with markup.
+	layout := binpack.Layout{
+		Width:  100,
+		Height: 20,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 30, Height: 20},
+			{Index: 1, X: 30, Y: 0, Width: 70, Height: 20},
+		},
+	}
+
+	// Act: render the layout as SVG.
+	svg := string(binpack.RenderSVG(layout, []string{"a<b>&c_long_name"}))
+
+	// Assert: the label is escaped and truncated, and the unnamed rectangle shows its index.
+	require.Contains(t, svg, `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="20" viewBox="0 0 100 20">`)
+	require.Contains(t, svg, `<rect x="0" y="0" width="30" height="20" fill="none" stroke="black"/>`)
+	require.Contains(t, svg, `>a&lt;b…</text>`)
+	require.Contains(t, svg, `<text x="30" y="12" font-size="12" font-family="monospace">1</text>`)
+}
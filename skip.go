@@ -0,0 +1,21 @@
+package binpack
+
+// Skipper is an optional interface for Packables with gaps, such as missing
+// items in a fixed grid, that must keep their index so the other indices stay
+// aligned. A skipped rectangle occupies no space and is left out of the
+// layout: Place is never called for it, so its position keeps whatever
+// sentinel the Packable initialised it with, such as (Unplaced, Unplaced).
+// With HeuristicGrid, the cell of a skipped rectangle is left empty.
+type Skipper interface {
+	// Skip returns true if the rectangle at index n is a gap.
+	Skip(n int) bool
+}
+
+// skipper returns a function that reports whether the rectangle at index n of
+// p is skipped.
+func skipper(p Packable) func(n int) bool {
+	if s, ok := p.(Skipper); ok {
+		return s.Skip
+	}
+	return func(int) bool { return false }
+}
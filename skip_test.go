@@ -0,0 +1,81 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// testSkipPackable extends testPackable with gaps.
+type testSkipPackable struct {
+	*testPackable
+	skipped map[int]bool
+}
+
+// Ensure that testSkipPackable implements the binpack.Skipper interface.
+var _ binpack.Skipper = (*testSkipPackable)(nil)
+
+// newTestSkipPackable creates a testSkipPackable whose gaps start at
+// (Unplaced, Unplaced).
+func newTestSkipPackable(rectangles []binpack.Rectangle, skipped ...int) *testSkipPackable {
+	tp := &testSkipPackable{testPackable: newTestPackable(rectangles), skipped: make(map[int]bool)}
+	for _, n := range skipped {
+		tp.skipped[n] = true
+		tp.placements[n].x, tp.placements[n].y = binpack.Unplaced, binpack.Unplaced
+	}
+	return tp
+}
+
+// Skip returns true if the rectangle at the specified index is a gap.
+func (tp *testSkipPackable) Skip(n int) bool {
+	return tp.skipped[n]
+}
+
+// TestSkip_Pack verifies that skipped rectangles occupy no space and are
+// neither placed nor reported as unplaced.
+func TestSkip_Pack(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create two rectangles separated by a large gap.
+	tp := newTestSkipPackable([]binpack.Rectangle{
+		{Width: 50, Height: 50},
+		{Width: 500, Height: 500},
+		{Width: 50, Height: 50},
+	}, 1)
+
+	// Act: pack the rectangles.
+	layout, unplaced := binpack.PackBestEffort(tp, binpack.Options{MaxWidth: 100, MaxHeight: 100})
+
+	// Assert: the gap should be left out of the layout and keep its sentinel.
+	require.Empty(t, unplaced)
+	require.Equal(t, 50*100, layout.Width*layout.Height)
+	require.Len(t, layout.Placements, 2)
+	for _, placement := range layout.Placements {
+		require.NotEqual(t, 1, placement.Index)
+	}
+	require.Equal(t, binpack.Unplaced, tp.placements[1].x)
+	require.Equal(t, binpack.Unplaced, tp.placements[1].y)
+}
+
+// TestSkip_Grid verifies that a skipped rectangle leaves its grid cell empty.
+func TestSkip_Grid(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create a row of cells with a gap in the middle.
+	tp := newTestSkipPackable([]binpack.Rectangle{
+		{Width: 10, Height: 10},
+		{Width: 40, Height: 40},
+		{Width: 10, Height: 10},
+	}, 1)
+
+	// Act: pack the rectangles into a single row.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{Heuristic: binpack.HeuristicGrid, Columns: 3})
+
+	// Assert: the last rectangle should keep its cell and the gap should not size the cells.
+	require.NoError(t, err)
+	require.Equal(t, 30, layout.Width)
+	require.Equal(t, 10, layout.Height)
+	require.Equal(t, 20, tp.placements[2].x)
+	require.Equal(t, binpack.Unplaced, tp.placements[1].x)
+}
@@ -0,0 +1,56 @@
+package binpack
+
+// PackSoA arranges rectangles given as parallel slices of widths and heights,
+// rather than as a Packable, using the same algorithm as Pack. The sizes are
+// copied into a slice that is packed directly, and the position of rectangle
+// i is written to outX[i] and outY[i] from the layout rather than through
+// Place. Returns the overall dimensions. Panics if heights, outX or outY is
+// shorter than widths.
+func PackSoA(widths, heights []int, outX, outY []int) (int, int) {
+	heights, outX, outY = heights[:len(widths)], outX[:len(widths)], outY[:len(widths)]
+	if len(widths) == 0 {
+		return 0, 0
+	}
+
+	var rectangles = make(rectangleSlice, len(widths))
+	for i, width := range widths {
+		rectangles[i] = Rectangle{Width: width, Height: heights[i]}
+	}
+	var layout, _, _ = packAround(rectangles, Options{}, nil, bounds{})
+	for _, placement := range layout.Placements {
+		outX[placement.Index], outY[placement.Index] = placement.X, placement.Y
+	}
+	return layout.Width, layout.Height
+}
+
+// PackGen arranges count rectangles whose sizes are computed on demand by
+// size, using the same algorithm as Pack, without a backing slice. The
+// position of each rectangle is passed to place. size may be called more
+// than once for the same index and must return the same rectangle each time.
+// Returns the overall dimensions.
+func PackGen(count int, size func(i int) Rectangle, place func(i, x, y int)) (int, int) {
+	return Pack(generator{count: count, size: size, place: place})
+}
+
+// generator adapts functions that compute sizes and record positions to the
+// Packable interface.
+type generator struct {
+	count int
+	size  func(i int) Rectangle
+	place func(i, x, y int)
+}
+
+// Len returns the number of rectangles.
+func (g generator) Len() int {
+	return g.count
+}
+
+// Rectangle returns the rectangle at index n.
+func (g generator) Rectangle(n int) Rectangle {
+	return g.size(n)
+}
+
+// Place records the position of the rectangle at index n.
+func (g generator) Place(n, x, y int) {
+	g.place(n, x, y)
+}
@@ -0,0 +1,83 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackSoA verifies that parallel slices are packed into the same layout
+// as the equivalent Packable.
+func TestPackSoA(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: split random rectangles into widths and heights.
+	rectangles := randomRectangles(20)
+	widths, heights := make([]int, len(rectangles)), make([]int, len(rectangles))
+	for i, r := range rectangles {
+		widths[i], heights[i] = r.Width, r.Height
+	}
+	outX, outY := make([]int, len(rectangles)), make([]int, len(rectangles))
+
+	// Act: pack the parallel slices.
+	w, h := binpack.PackSoA(widths, heights, outX, outY)
+
+	// Assert: the positions match those given to a Packable.
+	tp := newTestPackable(rectangles)
+	expectedW, expectedH := binpack.Pack(tp)
+	require.Equal(t, expectedW, w)
+	require.Equal(t, expectedH, h)
+	for i, p := range tp.placements {
+		require.Equal(t, p.x, outX[i])
+		require.Equal(t, p.y, outY[i])
+	}
+}
+
+// BenchmarkPackSoA measures packing parallel slices against packing the
+// equivalent Packable with Pack.
+func BenchmarkPackSoA(b *testing.B) {
+	rectangles := randomRectangles(100)
+	widths, heights := make([]int, len(rectangles)), make([]int, len(rectangles))
+	for i, r := range rectangles {
+		widths[i], heights[i] = r.Width, r.Height
+	}
+	outX, outY := make([]int, len(rectangles)), make([]int, len(rectangles))
+
+	b.Run("PackSoA", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			binpack.PackSoA(widths, heights, outX, outY)
+		}
+	})
+	b.Run("Pack", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			binpack.Pack(newTestPackable(rectangles))
+		}
+	})
+}
+
+// TestPackGen verifies that generated sizes are packed into the same layout
+// as the equivalent Packable.
+func TestPackGen(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: twenty random rectangles and somewhere to record their positions.
+	rectangles := randomRectangles(20)
+	positions := make([]struct{ x, y int }, len(rectangles))
+
+	// Act: pack the generated sizes.
+	w, h := binpack.PackGen(len(rectangles), func(i int) binpack.Rectangle {
+		return rectangles[i]
+	}, func(i, x, y int) {
+		positions[i].x, positions[i].y = x, y
+	})
+
+	// Assert: the positions match those given to a Packable.
+	tp := newTestPackable(rectangles)
+	expectedW, expectedH := binpack.Pack(tp)
+	require.Equal(t, expectedW, w)
+	require.Equal(t, expectedH, h)
+	require.Equal(t, tp.placements, positions)
+}
@@ -0,0 +1,26 @@
+package binpack
+
+// SplitToFit cuts r into a grid of pieces that each fit within a binW x binH
+// bin, such as to spread a huge source image over several textures. The
+// pieces are returned in row-major order and are binW x binH except along the
+// right and bottom edges, so the piece in row i and column j of the grid
+// starts at (j*binW, i*binH) within r, and there are ceil(r.Width / binW)
+// pieces per row. A rectangle that already fits, or has no area, is returned
+// as the only piece. Returns nil if binW or binH is not positive.
+func SplitToFit(r Rectangle, binW, binH int) []Rectangle {
+	if binW <= 0 || binH <= 0 {
+		return nil
+	}
+	if r.Width <= 0 || r.Height <= 0 || (r.Width <= binW && r.Height <= binH) {
+		return []Rectangle{r}
+	}
+
+	var columns, rows = (r.Width + binW - 1) / binW, (r.Height + binH - 1) / binH
+	var pieces = make([]Rectangle, 0, columns*rows)
+	for y := 0; y < r.Height; y += binH {
+		for x := 0; x < r.Width; x += binW {
+			pieces = append(pieces, Rectangle{Width: min(binW, r.Width-x), Height: min(binH, r.Height-y)})
+		}
+	}
+	return pieces
+}
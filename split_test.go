@@ -0,0 +1,51 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSplitToFit verifies that oversized rectangles are cut into pieces that
+// fit the bin, and that others are left whole.
+func TestSplitToFit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rectangle binpack.Rectangle
+		expected  []binpack.Rectangle
+	}{
+		{
+			name:      "Fits",
+			rectangle: binpack.Rectangle{Width: 100, Height: 50},
+			expected:  []binpack.Rectangle{{Width: 100, Height: 50}},
+		},
+		{
+			name:      "TooWide",
+			rectangle: binpack.Rectangle{Width: 250, Height: 50},
+			expected:  []binpack.Rectangle{{Width: 100, Height: 50}, {Width: 100, Height: 50}, {Width: 50, Height: 50}},
+		},
+		{
+			name:      "TooLarge",
+			rectangle: binpack.Rectangle{Width: 150, Height: 130},
+			expected: []binpack.Rectangle{
+				{Width: 100, Height: 100}, {Width: 50, Height: 100},
+				{Width: 100, Height: 30}, {Width: 50, Height: 30},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act: split the rectangle to fit a 100x100 bin.
+			pieces := binpack.SplitToFit(tt.rectangle, 100, 100)
+
+			// Assert: the rectangle is left whole or cut into pieces no larger than the bin.
+			require.Equal(t, tt.expected, pieces)
+		})
+	}
+	require.Nil(t, binpack.SplitToFit(binpack.Rectangle{Width: 10, Height: 10}, 0, 100))
+}
@@ -0,0 +1,87 @@
+package binpack
+
+import "math"
+
+const (
+	// autoGrowSlack is the fraction by which PackAutoGrow enlarges the
+	// natural bounding box to choose the bin.
+	autoGrowSlack = 0.05
+
+	// autoGrowAttempts is the number of times PackAutoGrow enlarges the bin
+	// when the rectangles do not fit before keeping the natural layout.
+	autoGrowAttempts = 4
+)
+
+// PackGrowingSquare packs the rectangles in p into a square, starting with
+// sides of length initial and doubling them until every rectangle fits, as
+// texture atlases often require. The rectangles are placed, and the layout is
+// returned along with the final length of the sides. Starting from a power of
+// two keeps the square a power of two. An initial length below one starts at
+// one.
+func PackGrowingSquare(p Packable, initial int) (Layout, int) {
+	var side = max(initial, 1)
+
+	// Skip the squares that cannot hold the largest rectangle without packing them.
+	var skip = skipper(p)
+	for i := 0; i < p.Len(); i++ {
+		if skip(i) {
+			continue
+		}
+		var r = p.Rectangle(i)
+		for side < max(r.Width, r.Height) && side <= math.MaxInt/2 {
+			side *= 2
+		}
+	}
+
+	for {
+		var layout, unplaced, _ = pack(p, Options{MaxWidth: side, MaxHeight: side})
+		if len(unplaced) == 0 || side > math.MaxInt/2 {
+			layout.apply(p)
+			return layout, side
+		}
+		side *= 2
+	}
+}
+
+// PackAutoGrow packs the rectangles in p into a bin sized from the layout,
+// for when the size of the bin is not known in advance. The rectangles are
+// first packed within the limits in opts to learn their natural bounding box,
+// then packed again into a bin 5% larger on each side, rounded by the Grid
+// and AlignTo options and kept within MaxWidth and MaxHeight. If they do not
+// all fit, the bin is enlarged again a few times before the natural layout is
+// kept, sized to its own dimensions. The rectangles are placed, and the
+// layout is returned along with the dimensions of the bin. Rectangles that do
+// not fit within the limits in opts are left unplaced, as with
+// PackBestEffort.
+func PackAutoGrow(p Packable, opts Options) (Layout, int, int) {
+	defer opts.Stats.begin()()
+	var natural, unplaced, _ = pack(p, opts)
+	var width, height = natural.Width, natural.Height
+	for range autoGrowAttempts {
+		width, height = opts.grow(width), opts.grow(height)
+		if opts.MaxWidth > 0 {
+			width = min(width, opts.MaxWidth)
+		}
+		if opts.MaxHeight > 0 {
+			height = min(height, opts.MaxHeight)
+		}
+		var bounded = opts
+		bounded.MaxWidth, bounded.MaxHeight = width, height
+		var layout, missing, _ = pack(p, bounded)
+		if len(missing) <= len(unplaced) {
+			layout.apply(p)
+			return layout, width, height
+		}
+	}
+	natural.apply(p)
+	return natural, natural.Width, natural.Height
+}
+
+// grow enlarges the dimension v of a bin by autoGrowSlack, by at least one,
+// and rounds it up to a multiple of the grid and of AlignTo.
+func (o Options) grow(v int) int {
+	if v <= 0 {
+		return v
+	}
+	return o.extent(v + max(int(math.Ceil(float64(v)*autoGrowSlack)), 1))
+}
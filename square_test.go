@@ -0,0 +1,92 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackGrowingSquare verifies that the square doubles until every
+// rectangle fits.
+func TestPackGrowingSquare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		rectangles []binpack.Rectangle
+		initial    int
+		want       int
+	}{
+		{name: "Fits", rectangles: []binpack.Rectangle{{Width: 30, Height: 30}, {Width: 30, Height: 30}}, initial: 64, want: 64},
+		{name: "Grows", rectangles: []binpack.Rectangle{{Width: 40, Height: 40}, {Width: 40, Height: 40}}, initial: 32, want: 128},
+		{name: "Large", rectangles: []binpack.Rectangle{{Width: 200, Height: 10}}, initial: 16, want: 256},
+		{name: "Zero", rectangles: []binpack.Rectangle{{Width: 3, Height: 3}}, initial: 0, want: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: create the test packable.
+			tp := newTestPackable(tt.rectangles)
+
+			// Act: pack the rectangles into a growing square.
+			layout, side := binpack.PackGrowingSquare(tp, tt.initial)
+
+			// Assert: the square should be the expected size and hold every rectangle.
+			require.Equal(t, tt.want, side)
+			require.Len(t, layout.Placements, len(tt.rectangles))
+			require.LessOrEqual(t, layout.Width, side)
+			require.LessOrEqual(t, layout.Height, side)
+			requireNoOverlap(t, layout)
+		})
+	}
+}
+
+// TestPackAutoGrow verifies that the rectangles are packed into a bin a little
+// larger than their natural bounding box, rounded by the alignment.
+func TestPackAutoGrow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts binpack.Options
+	}{
+		{name: "Unbounded", opts: binpack.Options{}},
+		{name: "Aligned", opts: binpack.Options{AlignTo: 8}},
+		{name: "Limited", opts: binpack.Options{MaxWidth: 300}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange: thirty random rectangles and their natural layout.
+			rectangles := randomRectangles(30)
+			natural, err := binpack.PackWithOptions(newTestPackable(rectangles), tt.opts)
+			require.NoError(t, err)
+			tp := newTestPackable(rectangles)
+
+			// Act: pack them into a bin grown from the layout.
+			layout, width, height := binpack.PackAutoGrow(tp, tt.opts)
+
+			// Assert: the bin should hold every rectangle and be at least the natural size.
+			require.Len(t, layout.Placements, len(rectangles))
+			require.LessOrEqual(t, layout.Width, width)
+			require.LessOrEqual(t, layout.Height, height)
+			require.GreaterOrEqual(t, width, natural.Width)
+			require.GreaterOrEqual(t, height, natural.Height)
+			if tt.opts.AlignTo > 0 {
+				require.Zero(t, width%tt.opts.AlignTo)
+				require.Zero(t, height%tt.opts.AlignTo)
+			}
+			if tt.opts.MaxWidth > 0 {
+				require.LessOrEqual(t, width, tt.opts.MaxWidth)
+			}
+			requireNoOverlap(t, layout)
+			for _, p := range layout.Placements {
+				require.Equal(t, p.X, tp.placements[p.Index].x)
+				require.Equal(t, p.Y, tp.placements[p.Index].y)
+			}
+		})
+	}
+}
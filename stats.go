@@ -0,0 +1,85 @@
+package binpack
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// PackStats counts the work done while packing, for profiling and comparing
+// heuristics. Set Options.Stats to collect them. The counters may be shared
+// by concurrent attempts, so they are updated atomically.
+type PackStats struct {
+	// Candidates is the number of candidate positions evaluated.
+	Candidates int64
+	// IntersectionTests is the number of pairs of rectangles tested for
+	// intersection.
+	IntersectionTests int64
+	// Placements is the number of rectangles that placement was attempted
+	// for. Rectangles in an exclusive group count once.
+	Placements int64
+	// Duration is the wall time spent packing.
+	Duration time.Duration
+}
+
+// begin resets the statistics and returns a function that records the wall
+// time when called.
+func (s *PackStats) begin() func() {
+	if s == nil {
+		return func() {}
+	}
+	*s = PackStats{}
+	var start = time.Now()
+	return func() {
+		s.Duration = time.Since(start)
+	}
+}
+
+// countCandidate records that a candidate position was evaluated.
+func (s *PackStats) countCandidate() {
+	if s != nil {
+		atomic.AddInt64(&s.Candidates, 1)
+	}
+}
+
+// countIntersectionTests records that n pairs of rectangles were tested for
+// intersection.
+func (s *PackStats) countIntersectionTests(n int) {
+	if s != nil {
+		atomic.AddInt64(&s.IntersectionTests, int64(n))
+	}
+}
+
+// countPlacement records that placement was attempted for a rectangle.
+func (s *PackStats) countPlacement() {
+	if s != nil {
+		atomic.AddInt64(&s.Placements, 1)
+	}
+}
+
+// EstimateCost returns an upper bound on the work PackWithOptions does to pack
+// p with the default heuristic, in the units of PackStats: candidate positions
+// evaluated plus intersection tests. Each rectangle may evaluate a candidate
+// at every pair of edges of the rectangles placed before it, and test each of
+// those against every one of them, so the bound grows with the fourth power of
+// the number of rectangles. Ranking skips most of that work in practice, so
+// the bound is best used to compare inputs and heuristics or to set timeouts,
+// rather than as a prediction. Skipped rectangles and those with no area are
+// not counted. The result saturates at math.MaxInt64.
+func EstimateCost(p Packable) int64 {
+	var skip = skipper(p)
+	var cost, placed int64
+	for i := 0; i < p.Len(); i++ {
+		if skip(i) || p.Rectangle(i).area64() == 0 {
+			continue
+		}
+		var edges = float64(2*placed + 1)
+		var step = edges * edges * float64(placed+1)
+		if step >= float64(math.MaxInt64-cost) {
+			return math.MaxInt64
+		}
+		cost += int64(step)
+		placed++
+	}
+	return cost
+}
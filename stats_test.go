@@ -0,0 +1,86 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackStats verifies that the statistics are populated by a packing run.
+func TestPackStats(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create stats with counts left over from a previous run.
+	tp := newTestPackable(randomRectangles(20))
+	stats := &binpack.PackStats{Candidates: -1, IntersectionTests: -1, Placements: -1}
+
+	// Act: pack the rectangles while collecting statistics.
+	_, err := binpack.PackWithOptions(tp, binpack.Options{Stats: stats})
+
+	// Assert: the statistics should describe the run.
+	require.NoError(t, err)
+	require.Equal(t, int64(20), stats.Placements)
+	require.Positive(t, stats.Candidates)
+	require.Positive(t, stats.IntersectionTests)
+	require.Positive(t, stats.Duration)
+}
+
+// TestPackStats_Best verifies that PackBest sums the statistics of every
+// attempt.
+func TestPackStats_Best(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create the test packable.
+	rectangles := randomRectangles(10)
+	stats := &binpack.PackStats{}
+
+	// Act: pack the rectangles with several concurrent attempts.
+	binpack.PackBest(newTestPackable(rectangles), binpack.Options{Attempts: 4, Parallelism: 4, Stats: stats})
+
+	// Assert: every attempt should be counted.
+	require.Equal(t, int64(40), stats.Placements)
+}
+
+// TestPackStats_MaxEvalPerRect verifies that no more than MaxEvalPerRect
+// candidates are evaluated for each rectangle.
+func TestPackStats_MaxEvalPerRect(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: create enough rectangles to produce many candidates.
+	tp := newTestPackable(randomRectangles(30))
+	stats := &binpack.PackStats{}
+
+	// Act: pack the rectangles with a cap on the candidates.
+	layout, err := binpack.PackWithOptions(tp, binpack.Options{MaxEvalPerRect: 5, Stats: stats})
+
+	// Assert: the cap should hold and the layout should still be valid.
+	require.NoError(t, err)
+	require.LessOrEqual(t, stats.Candidates, int64(5*30))
+	require.Len(t, layout.Placements, 30)
+	requireNoOverlap(t, layout)
+}
+
+// TestEstimateCost verifies that the estimate bounds the work actually done
+// and grows with the number of rectangles.
+func TestEstimateCost(t *testing.T) {
+	t.Parallel()
+
+	var previous int64
+	for _, n := range []int{1, 10, 50} {
+		// Arrange: random rectangles of the given number.
+		tp := newTestPackable(randomRectangles(n))
+
+		// Act: estimate the cost, then pack while counting the work.
+		estimate := binpack.EstimateCost(tp)
+		var stats binpack.PackStats
+		_, err := binpack.PackWithOptions(tp, binpack.Options{Stats: &stats})
+
+		// Assert: the estimate bounds the work and grows with the number of rectangles.
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, estimate, stats.Candidates+stats.IntersectionTests)
+		require.Greater(t, estimate, previous)
+		previous = estimate
+	}
+	require.Zero(t, binpack.EstimateCost(newTestPackable(nil)))
+}
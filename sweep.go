@@ -0,0 +1,28 @@
+package binpack
+
+// shelfSweep is the last resort for placing r in a bounded layout when the
+// candidate search finds nothing, such as when Options.MaxEvalPerRect cuts it
+// short. It scans the rows at each edge from top to bottom, and the columns in
+// each row from left to right, and returns the first position allowed by c
+// that stays within the limits and the regions without overlapping any
+// placement at all. Returns false if there is no such position.
+func shelfSweep(r Rectangle, placements []placement, candidates *candidateSet, c constraints, opts Options) (int, int, bool) {
+	for _, y := range candidates.yEdges {
+		for _, x := range candidates.xEdges {
+			var candidate = placement{
+				x:      roundToGrid(x, opts.Grid, RoundUp),
+				y:      roundToGrid(y, opts.Grid, RoundUp),
+				width:  r.Width,
+				height: r.Height,
+			}
+			var bb = bounds{minX: candidate.x, minY: candidate.y, maxX: candidate.x + candidate.width, maxY: candidate.y + candidate.height}
+			for _, p := range placements {
+				bb = expandBoundsForPlacement(p, bb)
+			}
+			if opts.fits(bb) && opts.contains(candidate) && c.permits(candidate.x, candidate.y) && !hasIntersection(candidate, placements, opts.Stats) {
+				return candidate.x, candidate.y, true
+			}
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,106 @@
+// Package testutil helps to regression-test packing by comparing layouts
+// against known-good results with readable diffs.
+package testutil
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+)
+
+// Equal reports whether the layouts a and b have the same dimensions, place
+// every rectangle at the same position with the same size, and share the same
+// placement references. The order of the placements is not compared.
+func Equal(a, b binpack.Layout) bool {
+	return len(diff(a, b)) == 0
+}
+
+// AssertLayout marks the test as failed if the layout got is not Equal to
+// want, listing every difference between them, such as a rectangle placed at
+// another position or missing from got.
+func AssertLayout(t testing.TB, got, want binpack.Layout) {
+	t.Helper()
+	if differences := diff(got, want); len(differences) > 0 {
+		t.Errorf("layouts differ:\n\t%s", strings.Join(differences, "\n\t"))
+	}
+}
+
+// diff returns a line for every difference between the layouts got and want,
+// in ascending order of rectangle index.
+func diff(got, want binpack.Layout) []string {
+	var differences []string
+	if got.Width != want.Width || got.Height != want.Height {
+		differences = append(differences, fmt.Sprintf("size: got %dx%d, want %dx%d", got.Width, got.Height, want.Width, want.Height))
+	}
+
+	var gotPlacements, wantPlacements = byIndex(got.Placements), byIndex(want.Placements)
+	for _, index := range indices(gotPlacements, wantPlacements) {
+		var g, inGot = gotPlacements[index]
+		var w, inWant = wantPlacements[index]
+		switch {
+		case !inGot:
+			differences = append(differences, fmt.Sprintf("rectangle %d: missing, want %s", index, format(w)))
+		case !inWant:
+			differences = append(differences, fmt.Sprintf("rectangle %d: unexpected at %s", index, format(g)))
+		case g != w:
+			differences = append(differences, fmt.Sprintf("rectangle %d: got %s, want %s", index, format(g), format(w)))
+		}
+	}
+
+	var gotRefs, wantRefs = refsByIndex(got.Refs), refsByIndex(want.Refs)
+	for _, index := range indices(gotRefs, wantRefs) {
+		var g, inGot = gotRefs[index]
+		var w, inWant = wantRefs[index]
+		switch {
+		case !inGot:
+			differences = append(differences, fmt.Sprintf("rectangle %d: missing reference, want %d", index, w))
+		case !inWant:
+			differences = append(differences, fmt.Sprintf("rectangle %d: unexpected reference to %d", index, g))
+		case g != w:
+			differences = append(differences, fmt.Sprintf("rectangle %d: got reference to %d, want %d", index, g, w))
+		}
+	}
+	return differences
+}
+
+// format returns the position and dimensions of a placement.
+func format(p binpack.Placement) string {
+	return fmt.Sprintf("(%d, %d) %dx%d", p.X, p.Y, p.Width, p.Height)
+}
+
+// byIndex returns the placements keyed by the index of their rectangle.
+func byIndex(placements []binpack.Placement) map[int]binpack.Placement {
+	var m = make(map[int]binpack.Placement, len(placements))
+	for _, p := range placements {
+		m[p.Index] = p
+	}
+	return m
+}
+
+// refsByIndex returns the index each reference shares the placement of,
+// keyed by the index of the repeated rectangle.
+func refsByIndex(refs []binpack.PlacementRef) map[int]int {
+	var m = make(map[int]int, len(refs))
+	for _, ref := range refs {
+		m[ref.Index] = ref.Ref
+	}
+	return m
+}
+
+// indices returns the keys of a and b in ascending order, without duplicates.
+func indices[V any](a, b map[int]V) []int {
+	var keys = make([]int, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
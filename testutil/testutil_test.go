@@ -0,0 +1,134 @@
+package testutil_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/lewisgibson/go-binpack/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a testing.TB that records the failures reported to it.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+// Helper does nothing.
+func (r *recorder) Helper() {}
+
+// Errorf records the failure.
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// TestEqual verifies that layouts are compared placement by placement,
+// regardless of the order of the placements.
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	var want = binpack.Layout{
+		Width: 20, Height: 10,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 10, Y: 0, Width: 10, Height: 10},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		got   binpack.Layout
+		equal bool
+	}{
+		{
+			name:  "Same",
+			got:   want,
+			equal: true,
+		},
+		{
+			name: "Reordered",
+			got: binpack.Layout{
+				Width: 20, Height: 10,
+				Placements: []binpack.Placement{want.Placements[1], want.Placements[0]},
+			},
+			equal: true,
+		},
+		{
+			name: "Moved",
+			got: binpack.Layout{
+				Width: 20, Height: 10,
+				Placements: []binpack.Placement{want.Placements[0], {Index: 1, X: 10, Y: 5, Width: 10, Height: 10}},
+			},
+		},
+		{
+			name: "Missing",
+			got: binpack.Layout{
+				Width: 20, Height: 10,
+				Placements: want.Placements[:1],
+			},
+		},
+		{
+			name: "Resized",
+			got: binpack.Layout{
+				Width: 30, Height: 10,
+				Placements: want.Placements,
+			},
+		},
+		{
+			name: "Refs",
+			got: binpack.Layout{
+				Width: 20, Height: 10,
+				Placements: want.Placements,
+				Refs:       []binpack.PlacementRef{{Index: 2, Ref: 0}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act:
+			var equal = testutil.Equal(tt.got, want)
+
+			// Assert:
+			require.Equal(t, tt.equal, equal)
+		})
+	}
+}
+
+// TestAssertLayout verifies that every difference between the layouts is
+// reported in one failure.
+func TestAssertLayout(t *testing.T) {
+	t.Parallel()
+
+	// Arrange:
+	var want = binpack.Layout{
+		Width: 20, Height: 10,
+		Placements: []binpack.Placement{
+			{Index: 0, X: 0, Y: 0, Width: 10, Height: 10},
+			{Index: 1, X: 10, Y: 0, Width: 10, Height: 10},
+		},
+	}
+	var got = binpack.Layout{
+		Width: 20, Height: 20,
+		Placements: []binpack.Placement{
+			{Index: 1, X: 0, Y: 10, Width: 10, Height: 10},
+			{Index: 2, X: 10, Y: 10, Width: 10, Height: 10},
+		},
+	}
+	var r = &recorder{TB: t}
+
+	// Act:
+	testutil.AssertLayout(r, got, want)
+	testutil.AssertLayout(r, want, want)
+
+	// Assert:
+	require.Equal(t, []string{"layouts differ:\n" +
+		"\tsize: got 20x20, want 20x10\n" +
+		"\trectangle 0: missing, want (0, 0) 10x10\n" +
+		"\trectangle 1: got (0, 10) 10x10, want (10, 0) 10x10\n" +
+		"\trectangle 2: unexpected at (10, 10) 10x10",
+	}, r.errors)
+}
@@ -0,0 +1,17 @@
+package binpack
+
+// PackTrace arranges rectangles like Pack, and also returns the layout after
+// each rectangle is placed, such as for animating the layout being built. Each
+// intermediate layout holds every rectangle placed so far, positioned as it
+// would be if packing ended there, so earlier rectangles may shift as the
+// bounding box grows. The final layout is returned separately.
+func PackTrace(p Packable) ([]Layout, Layout) {
+	var steps []Layout
+	var opts = Options{StopWhen: func(partial Layout) bool {
+		steps = append(steps, partial)
+		return false
+	}}
+	var layout, _, _ = pack(p, opts)
+	layout.apply(p)
+	return steps, layout
+}
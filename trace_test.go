@@ -0,0 +1,34 @@
+package binpack_test
+
+import (
+	"testing"
+
+	"github.com/lewisgibson/go-binpack"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPackTrace verifies that a layout is recorded for every placement and
+// that the last one matches the final layout.
+func TestPackTrace(t *testing.T) {
+	t.Parallel()
+
+	// Arrange: ten random rectangles.
+	rectangles := randomRectangles(10)
+	tp := newTestPackable(rectangles)
+
+	// Act: pack them, recording each step.
+	steps, layout := binpack.PackTrace(tp)
+
+	// Assert: each step holds one more rectangle and never shrinks.
+	require.Len(t, steps, len(rectangles))
+	for i, step := range steps {
+		require.Len(t, step.Placements, i+1)
+		requireNoOverlap(t, step)
+		if i > 0 {
+			require.GreaterOrEqual(t, step.Width, steps[i-1].Width)
+			require.GreaterOrEqual(t, step.Height, steps[i-1].Height)
+		}
+	}
+	require.Equal(t, layout, steps[len(steps)-1])
+	require.Equal(t, layout.Placements[0].X, tp.placements[0].x)
+}